@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"smart-pc-agent/internal/config"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	luaMqtt "smart-pc-agent/internal/mqtt/commands/lua-api/mqtt"
+)
+
+// runLuaLS печатает файл определений для Lua Language Server, не запуская
+// агент: smart-pc luals [-o spc.lua]
+func runLuaLS(args []string) int {
+	flags := flag.NewFlagSet("luals", flag.ContinueOnError)
+	output := flags.String("o", "", "output file, stdout by default")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	// модули нужны только ради документации, поэтому конфиг пустой,
+	// а хранилище и MQTT не подключаются
+	registry := newRegistry(slog.New(slog.DiscardHandler), &config.Config{}, nil, luaMqtt.New())
+
+	definitions := luaApi.LuaLS(registry.Schema(), nil)
+
+	if *output == "" {
+		fmt.Print(definitions)
+		return 0
+	}
+	if err := os.WriteFile(*output, []byte(definitions), 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to write definitions:", err)
+		return 1
+	}
+	return 0
+}
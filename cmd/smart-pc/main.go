@@ -9,11 +9,27 @@ import (
 	authorization "smart-pc-agent/internal/auth"
 	"smart-pc-agent/internal/config"
 	httpServer "smart-pc-agent/internal/http-server"
+	localToken "smart-pc-agent/internal/lib/local-token"
 	"smart-pc-agent/internal/lib/logger"
 	luaApi "smart-pc-agent/internal/lib/lua-api"
 	"smart-pc-agent/internal/lib/waitable"
 	"smart-pc-agent/internal/mqtt"
+	luaClipboard "smart-pc-agent/internal/mqtt/commands/lua-api/clipboard"
+	luaFs "smart-pc-agent/internal/mqtt/commands/lua-api/fs"
+	luaHttp "smart-pc-agent/internal/mqtt/commands/lua-api/http"
+	luaInput "smart-pc-agent/internal/mqtt/commands/lua-api/input"
+	luaJson "smart-pc-agent/internal/mqtt/commands/lua-api/json"
 	luaLog "smart-pc-agent/internal/mqtt/commands/lua-api/log"
+	luaMedia "smart-pc-agent/internal/mqtt/commands/lua-api/media"
+	luaMqtt "smart-pc-agent/internal/mqtt/commands/lua-api/mqtt"
+	luaNotify "smart-pc-agent/internal/mqtt/commands/lua-api/notify"
+	luaProcess "smart-pc-agent/internal/mqtt/commands/lua-api/process"
+	luaResult "smart-pc-agent/internal/mqtt/commands/lua-api/result"
+	luaStorage "smart-pc-agent/internal/mqtt/commands/lua-api/storage"
+	luaSystem "smart-pc-agent/internal/mqtt/commands/lua-api/system"
+	luaTime "smart-pc-agent/internal/mqtt/commands/lua-api/time"
+	luaVolume "smart-pc-agent/internal/mqtt/commands/lua-api/volume"
+	"smart-pc-agent/internal/mqtt/commands/runner"
 	pcsService "smart-pc-agent/internal/services/pcs-service"
 	"smart-pc-agent/internal/storage/sqlite"
 	"syscall"
@@ -24,6 +40,10 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "luals" {
+		os.Exit(runLuaLS(os.Args[2:]))
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
@@ -53,18 +73,29 @@ func main() {
 		os.Exit(1)
 	}
 
-	registry := luaApi.NewRegistry("v0.0.0").
-		Register("log", luaLog.New(log))
+	token, err := localToken.Load(cfg.HTTPServer.TokenPath)
+	if err != nil {
+		log.Error("failed to load local token", sl.Err(err))
+		os.Exit(1)
+	}
+
+	mqttModule := luaMqtt.New()
+	registry := newRegistry(log, cfg, storage.ScriptStorage, mqttModule)
+
+	scriptRunner := runner.New(log, cfg.Scripts, storage.Libraries, registry)
 
 	mqttConn, err := mqtt.New(
 		ctx,
 		log,
 		cfg.MQTT,
+		cfg.History,
 		auth,
-		registry,
+		mqttModule,
 		storage.AppStorage,
 		storage.Commands,
 		storage.CommandParameters,
+		scriptRunner,
+		storage.CommandExecutions,
 	)
 	if err != nil {
 		log.Error("failed to create mqtt connection", sl.Err(err))
@@ -75,7 +106,18 @@ func main() {
 		log.Info("mqtt connection closed")
 	}()
 
-	srv := httpServer.New(ctx, log, cfg.HTTPServer, storage, pcs, registry, stop)
+	srv := httpServer.New(
+		ctx,
+		log,
+		cfg.HTTPServer,
+		cfg.Scripts,
+		token,
+		storage,
+		pcs,
+		registry,
+		scriptRunner,
+		stop,
+	)
 	go func() {
 		if err := srv.Run(ctx); err != nil {
 			log.Error("http server error", sl.Err(err))
@@ -83,12 +125,36 @@ func main() {
 		}
 	}()
 
-	go systray.Run(onTrayReady(ctx, log), onTrayExit(stop))
+	go systray.Run(onTrayReady(ctx, log, token), onTrayExit(stop))
 
 	waitable.WaitAll(mqttConn, srv)
 }
 
-func onTrayReady(ctx context.Context, log *slog.Logger) func() {
+func newRegistry(
+	log *slog.Logger,
+	cfg *config.Config,
+	scriptStorage luaStorage.ScriptStorage,
+	mqttModule *luaMqtt.Module,
+) *luaApi.Registry {
+	return luaApi.NewRegistry("v0.0.0").
+		Register("log", luaLog.New(log)).
+		Register("result", luaResult.New()).
+		Register("json", luaJson.New()).
+		Register("media", luaMedia.New()).
+		Register("volume", luaVolume.New()).
+		Register("system", luaSystem.New()).
+		Register("storage", luaStorage.New(scriptStorage)).
+		Register("time", luaTime.New()).
+		Register("notify", luaNotify.New()).
+		RegisterCapability("fs", luaFs.New(cfg.Scripts.FS)).
+		RegisterCapability("process", luaProcess.New(cfg.Scripts.Process)).
+		RegisterCapability("http", luaHttp.New(cfg.Scripts.HTTP)).
+		RegisterCapability("clipboard", luaClipboard.New()).
+		RegisterCapability("input", luaInput.New()).
+		RegisterCapability("mqtt", mqttModule)
+}
+
+func onTrayReady(ctx context.Context, log *slog.Logger, token string) func() {
 	return func() {
 		systray.SetIcon(assets.GetIcon())
 		systray.SetTitle("Smart PC")
@@ -101,6 +167,9 @@ func onTrayReady(ctx context.Context, log *slog.Logger) func() {
 		mQuit := systray.AddMenuItem("Quit", "Quit")
 		mQuit.SetIcon(assets.GetExit())
 
+		// интерфейс берёт токен из фрагмента, который не уходит на сервер
+		fragment := "#token=" + token
+
 		go func() {
 			const op = "tray"
 			log := log.With(sl.Op(op))
@@ -116,12 +185,12 @@ func onTrayReady(ctx context.Context, log *slog.Logger) func() {
 					return
 				case <-mOpenInterface.ClickedCh:
 					log.Info("open interface clicked")
-					if err := browser.Open("http://localhost:3003/this-pc"); err != nil {
+					if err := browser.Open("http://localhost:3003/this-pc" + fragment); err != nil {
 						log.Error("failed to open browser", sl.Err(err))
 					}
 				case <-mOpenDashboard.ClickedCh:
 					log.Info("open dashboard clicked")
-					if err := browser.Open("http://localhost:3003/dashboard"); err != nil {
+					if err := browser.Open("http://localhost:3003/dashboard" + fragment); err != nil {
 						log.Error("failed to open browser", sl.Err(err))
 					}
 				}
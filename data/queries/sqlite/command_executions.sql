@@ -0,0 +1,48 @@
+-- name: CreateCommandExecution :one
+INSERT INTO command_executions(command_id, message_id, parameters, status, started_at)
+VALUES (@command_id, @message_id, @parameters, @status, @started_at)
+RETURNING *;
+
+-- name: FinishCommandExecution :one
+UPDATE command_executions
+SET status        = @status,
+    error_message = @error_message,
+    finished_at   = @finished_at
+WHERE id = @id
+RETURNING *;
+
+-- name: GetCommandExecutions :many
+SELECT *
+FROM command_executions
+WHERE command_id = @command_id
+ORDER BY started_at DESC, id DESC
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: CountCommandExecutions :one
+SELECT COUNT(*)
+FROM command_executions
+WHERE command_id = @command_id;
+
+-- name: DeleteCommandExecutions :exec
+DELETE
+FROM command_executions
+WHERE command_id = @command_id;
+
+-- name: DeleteCommandExecutionsStartedBefore :exec
+DELETE
+FROM command_executions
+WHERE started_at < @started_before;
+
+-- name: DeleteCommandExecutionsExceptLatest :exec
+DELETE
+FROM command_executions
+WHERE id IN (SELECT latest.id
+             FROM command_executions AS latest
+             WHERE latest.command_id = @command_id
+             ORDER BY latest.started_at DESC, latest.id DESC
+             LIMIT -1 OFFSET @keep);
+
+-- name: DeleteAllCommandExecutions :exec
+-- noinspection SqlWithoutWhere
+DELETE
+FROM command_executions
@@ -4,10 +4,18 @@ FROM command_params
 WHERE command_id = $command_id;
 
 -- name: CreateOrUpdateCommandParameter :one
-INSERT INTO command_params(command_id, name, type)
-VALUES ($command_id, $name, $type)
+INSERT INTO command_params(command_id, name, type, item_type, required, default_value, min_value,
+                           max_value, enum_values)
+VALUES ($command_id, $name, $type, $item_type, $required, $default_value, $min_value, $max_value,
+        $enum_values)
 ON CONFLICT(command_id, name)
-    DO UPDATE SET type = excluded.type
+    DO UPDATE SET type          = excluded.type,
+                  item_type     = excluded.item_type,
+                  required      = excluded.required,
+                  default_value = excluded.default_value,
+                  min_value     = excluded.min_value,
+                  max_value     = excluded.max_value,
+                  enum_values   = excluded.enum_values
 RETURNING *;
 
 -- name: DeleteCommandParameters :exec
@@ -4,8 +4,8 @@ FROM commands
 WHERE id = $id;
 
 -- name: CreateCommand :one
-INSERT INTO commands(id, script)
-VALUES (?, ?)
+INSERT INTO commands(id, script, timeout_ms, capabilities)
+VALUES (?, ?, ?, ?)
 RETURNING *;
 
 -- name: DeleteCommand :one
@@ -14,9 +14,11 @@ FROM commands
 WHERE id = @id
 RETURNING *;
 
--- name: UpdateCommandScript :one
+-- name: UpdateCommand :one
 UPDATE commands
-SET script = @script
+SET script       = @script,
+    timeout_ms   = @timeout_ms,
+    capabilities = @capabilities
 WHERE id = @id
 RETURNING *;
 
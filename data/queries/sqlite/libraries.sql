@@ -0,0 +1,35 @@
+-- name: GetLibraries :many
+SELECT *
+FROM libraries
+ORDER BY name;
+
+-- name: GetLibrary :one
+SELECT *
+FROM libraries
+WHERE name = @name;
+
+-- name: CreateLibrary :one
+INSERT INTO libraries(name, description, source, updated_at)
+VALUES (@name, @description, @source, @updated_at)
+ON CONFLICT (name) DO NOTHING
+RETURNING *;
+
+-- name: UpdateLibrary :one
+UPDATE libraries
+SET description = @description,
+    source      = @source,
+    version     = version + 1,
+    updated_at  = @updated_at
+WHERE name = @name
+RETURNING *;
+
+-- name: DeleteLibrary :one
+DELETE
+FROM libraries
+WHERE name = @name
+RETURNING *;
+
+-- name: DeleteAllLibraries :exec
+-- noinspection SqlWithoutWhere
+DELETE
+FROM libraries
@@ -0,0 +1,32 @@
+-- name: SetScriptStorageValue :exec
+INSERT OR
+REPLACE INTO script_storage(command_id, key, value)
+VALUES (@command_id, @key, @value);
+
+-- name: GetScriptStorageValue :one
+SELECT *
+FROM script_storage
+WHERE command_id = @command_id
+  AND key = @key;
+
+-- name: DeleteScriptStorageValue :exec
+DELETE
+FROM script_storage
+WHERE command_id = @command_id
+  AND key = @key;
+
+-- name: GetScriptStorageKeys :many
+SELECT key
+FROM script_storage
+WHERE command_id = @command_id
+ORDER BY key;
+
+-- name: DeleteScriptStorage :exec
+DELETE
+FROM script_storage
+WHERE command_id = @command_id;
+
+-- name: DeleteAllScriptStorage :exec
+-- noinspection SqlWithoutWhere
+DELETE
+FROM script_storage
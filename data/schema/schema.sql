@@ -6,15 +6,56 @@ CREATE TABLE IF NOT EXISTS app_storage
 
 CREATE TABLE IF NOT EXISTS commands
 (
-    id     TEXT PRIMARY KEY,
-    script VARCHAR(8192) NOT NULL
+    id           TEXT PRIMARY KEY,
+    script       VARCHAR(8192) NOT NULL,
+    timeout_ms   INTEGER       NOT NULL DEFAULT 0 CHECK (timeout_ms >= 0),
+    capabilities TEXT          NOT NULL DEFAULT ''
 );
 
 CREATE TABLE IF NOT EXISTS command_params
 (
-    command_id TEXT         NOT NULL REFERENCES commands (id),
-    name       VARCHAR(255) NOT NULL,
-    type       SMALLINT     NOT NULL CHECK (type >= 1 AND type <= 3),
+    command_id    TEXT         NOT NULL REFERENCES commands (id),
+    name          VARCHAR(255) NOT NULL,
+    type          SMALLINT     NOT NULL CHECK (type >= 1 AND type <= 8),
+    item_type     SMALLINT CHECK (item_type IN (2, 3, 4)),
+    required      BOOLEAN      NOT NULL DEFAULT FALSE,
+    default_value TEXT,
+    min_value     REAL,
+    max_value     REAL,
+    enum_values   TEXT         NOT NULL DEFAULT '',
 
     PRIMARY KEY (command_id, name)
-);
\ No newline at end of file
+);
+
+CREATE TABLE IF NOT EXISTS command_executions
+(
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    command_id    TEXT        NOT NULL,
+    message_id    TEXT        NOT NULL DEFAULT '',
+    parameters    TEXT        NOT NULL DEFAULT '',
+    status        VARCHAR(16) NOT NULL,
+    error_message TEXT        NOT NULL DEFAULT '',
+    started_at    DATETIME    NOT NULL,
+    finished_at   DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS command_executions_command_id_started_at_idx
+    ON command_executions (command_id, started_at);
+
+CREATE TABLE IF NOT EXISTS script_storage
+(
+    command_id TEXT         NOT NULL,
+    key        VARCHAR(255) NOT NULL,
+    value      TEXT         NOT NULL,
+
+    PRIMARY KEY (command_id, key)
+);
+
+CREATE TABLE IF NOT EXISTS libraries
+(
+    name        VARCHAR(255)  PRIMARY KEY,
+    description VARCHAR(1024) NOT NULL DEFAULT '',
+    source      TEXT          NOT NULL,
+    version     INTEGER       NOT NULL DEFAULT 1,
+    updated_at  DATETIME      NOT NULL
+);
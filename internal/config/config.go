@@ -18,6 +18,8 @@ type Config struct {
 	MQTT       MQTT       `yaml:"mqtt"`
 	Storage    Storage    `yaml:"storage"`
 	Services   Services   `yaml:"services"`
+	Scripts    Scripts    `yaml:"scripts"`
+	History    History    `yaml:"history"`
 }
 
 type HTTPServer struct {
@@ -25,6 +27,10 @@ type HTTPServer struct {
 	Timeout         time.Duration `yaml:"timeout"          env-default:"4s"`
 	IdleTimeout     time.Duration `yaml:"idle_timeout"     env-default:"60s"`
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"1s"`
+	// AllowedOrigins — страницы, которым браузер разрешит обращаться к серверу
+	AllowedOrigins []string `yaml:"allowed_origins" env-default:"http://localhost:3003,http://127.0.0.1:3003"`
+	// TokenPath — файл с токеном установки, без которого нельзя выполнять скрипты
+	TokenPath string `yaml:"token_path" env-default:"./data/storage/local-token"`
 }
 
 type Auth struct {
@@ -72,6 +78,55 @@ type PcsService struct {
 	BaseURL string        `yaml:"base_url" env-default:"http://localhost:9080/pcs"`
 }
 
+type Scripts struct {
+	Timeout time.Duration  `yaml:"timeout" env-default:"30s"`
+	FS      ScriptsFS      `yaml:"fs"`
+	HTTP    ScriptsHTTP    `yaml:"http"`
+	Process ScriptsProcess `yaml:"process"`
+	Limits  ScriptsLimits  `yaml:"limits"`
+	REPL    ScriptsREPL    `yaml:"repl"`
+	// LocalCapabilities выдаются скриптам dry-run и REPL, которые не
+	// сохранены как команда и поэтому не имеют своих возможностей
+	LocalCapabilities []string `yaml:"local_capabilities"`
+}
+
+type ScriptsFS struct {
+	Roots []string `yaml:"roots" env-default:"./data/files"`
+}
+
+type ScriptsHTTP struct {
+	AllowedHosts    []string      `yaml:"allowed_hosts"`
+	Timeout         time.Duration `yaml:"timeout"           env-default:"10s"`
+	MaxResponseSize int64         `yaml:"max_response_size" env-default:"1048576"`
+}
+
+// ScriptsProcess ограничивает вывод процесса, который сохраняется
+// для скрипта, 0 — без ограничения
+type ScriptsProcess struct {
+	MaxOutputSize int64 `yaml:"max_output_size" env-default:"1048576"`
+}
+
+// ScriptsLimits ограничивает ресурсы одного выполнения скрипта, 0 — без ограничения
+type ScriptsLimits struct {
+	CallStackSize   int   `yaml:"call_stack_size"   env-default:"256"`
+	RegistrySize    int   `yaml:"registry_size"     env-default:"5120"`
+	RegistryMaxSize int   `yaml:"registry_max_size" env-default:"262144"`
+	Instructions    int64 `yaml:"instructions"      env-default:"1000000000"`
+	Memory          int64 `yaml:"memory"            env-default:"268435456"`
+}
+
+// ScriptsREPL настраивает интерактивные сессии, каждый ввод выполняется
+// с таймаутом и лимитами обычного скрипта
+type ScriptsREPL struct {
+	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"10m"`
+}
+
+// History ограничивает историю выполнения команд, 0 — без ограничения
+type History struct {
+	MaxAge        time.Duration `yaml:"max_age"         env-default:"720h"`
+	MaxPerCommand int64         `yaml:"max_per_command" env-default:"1000"`
+}
+
 func MustLoad() *Config {
 	configPath := os.Getenv("CONFIG_PATH")
 
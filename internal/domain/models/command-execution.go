@@ -0,0 +1,23 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ExecutionStatusRunning = "running"
+	ExecutionStatusSuccess = "success"
+	ExecutionStatusError   = "error"
+)
+
+type CommandExecution struct {
+	ID           int64           `json:"id"`
+	CommandID    string          `json:"commandId"`
+	MessageID    string          `json:"messageId,omitempty"`
+	Parameters   json.RawMessage `json:"parameters,omitempty"`
+	Status       string          `json:"status"`
+	ErrorMessage string          `json:"errorMessage,omitempty"`
+	StartedAt    time.Time       `json:"startedAt"`
+	FinishedAt   *time.Time      `json:"finishedAt,omitempty"`
+}
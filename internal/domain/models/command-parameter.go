@@ -6,6 +6,17 @@ type CommandParameter struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Type        int16  `json:"type"`
+	// ItemType — тип элементов списка, для остальных типов равен 0
+	ItemType int16 `json:"itemType,omitempty"`
+
+	// ограничения хранятся только локально; Min и Max ограничивают
+	// значение числа, длину строки, количество элементов списка
+	// или длительность в секундах
+	Required bool     `json:"required,omitempty"`
+	Default  *string  `json:"default,omitempty"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+	Enum     []string `json:"enum,omitempty"`
 
 	Command *Command `json:"command,omitempty"`
 }
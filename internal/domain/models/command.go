@@ -1,11 +1,13 @@
 package models
 
 type Command struct {
-	ID          string `json:"id"`
-	PcID        string `json:"pcId"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Script      string `json:"script"`
+	ID           string   `json:"id"`
+	PcID         string   `json:"pcId"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Script       string   `json:"script"`
+	TimeoutMs    int64    `json:"timeoutMs,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
 
 	Parameters []CommandParameter `json:"parameters,omitempty"`
 }
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type Library struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Source      string    `json:"source"`
+	Version     int64     `json:"version"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,80 @@
+package luals
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"smart-pc-agent/internal/domain/models"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	"smart-pc-agent/internal/mqtt/commands/runner"
+	"smart-pc-agent/internal/storage"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/api/response"
+	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
+	"github.com/go-chi/render"
+)
+
+type SchemaGetter interface {
+	Schema() luaApi.APISchema
+}
+
+type CommandGetter interface {
+	GetCommandById(ctx context.Context, id string) (models.Command, error)
+}
+
+type CommandParamsGetter interface {
+	GetCommandParams(ctx context.Context, commandId string) ([]models.CommandParameter, error)
+}
+
+// New отдаёт файл определений для Lua Language Server. С query-параметром
+// command_id spc.params описывается параметрами этой команды.
+func New(
+	log *slog.Logger,
+	schemaGetter SchemaGetter,
+	commandGetter CommandGetter,
+	paramsGetter CommandParamsGetter,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.api.schema.luals"
+		log := log.With(sl.Op(op), sl.ReqID(r))
+
+		var params []luaApi.ParamDoc
+		if commandID := r.URL.Query().Get("command_id"); commandID != "" {
+			_, err := commandGetter.GetCommandById(r.Context(), commandID)
+			if errors.Is(err, storage.ErrNotFound) {
+				log.Warn("command not found", slog.String("command", commandID))
+				render.JSON(w, r, response.NotFound("command not found"))
+				return
+			}
+			if err != nil {
+				log.Error("failed to get command", sl.Err(err))
+				render.JSON(w, r, response.InternalError())
+				return
+			}
+
+			commandParams, err := paramsGetter.GetCommandParams(r.Context(), commandID)
+			if err != nil {
+				log.Error("failed to get command parameters", sl.Err(err))
+				render.JSON(w, r, response.InternalError())
+				return
+			}
+
+			params = make([]luaApi.ParamDoc, len(commandParams))
+			for i, p := range commandParams {
+				params[i] = luaApi.ParamDoc{
+					Name:        p.Name,
+					Type:        runner.ParamType(p),
+					Description: p.Description,
+					Optional:    !p.Required && p.Default == nil,
+					Enum:        p.Enum,
+				}
+			}
+		}
+
+		definitions := luaApi.LuaLS(schemaGetter.Schema(), params)
+
+		w.Header().Set("Content-Disposition", `attachment; filename="spc.lua"`)
+		render.PlainText(w, r, definitions)
+	}
+}
@@ -1,21 +1,51 @@
 package schema
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
+	"smart-pc-agent/internal/domain/models"
 	luaApi "smart-pc-agent/internal/lib/lua-api"
+	"smart-pc-agent/internal/mqtt/commands/runner"
 
 	"github.com/MaxRomanov007/smart-pc-go-lib/api/response"
 	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
 	"github.com/go-chi/render"
 )
 
-func New(log *slog.Logger, registry *luaApi.Registry) http.HandlerFunc {
+type LibrariesGetter interface {
+	GetLibraries(ctx context.Context) ([]models.Library, error)
+}
+
+func New(
+	log *slog.Logger,
+	registry *luaApi.Registry,
+	librariesGetter LibrariesGetter,
+) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "http-server.handlers.api.schema"
 		log := log.With(sl.Op(op), sl.ReqID(r))
 
 		schema := registry.Schema()
+
+		libraries, err := librariesGetter.GetLibraries(r.Context())
+		if err != nil {
+			log.Error("failed to get libraries", sl.Err(err))
+			render.JSON(w, r, response.InternalError())
+			return
+		}
+
+		schema.Libraries = make([]luaApi.LibraryDoc, len(libraries))
+		for i, library := range libraries {
+			schema.Libraries[i] = luaApi.LibraryDoc{
+				Name:        library.Name,
+				Description: library.Description,
+				Version:     library.Version,
+			}
+		}
+
+		schema.ParamTypes = runner.ParamTypes()
+
 		log.Debug("got schema", slog.Any("schema", schema))
 		render.JSON(w, r, response.OK(&schema))
 	}
@@ -19,15 +19,20 @@ type CommandParametersGetter interface {
 	GetCommandParameters(ctx context.Context, id string) ([]models.CommandParameter, error)
 }
 
-type CommandScriptGetter interface {
-	GetCommandScript(ctx context.Context, id string) (string, error)
+type LocalCommandGetter interface {
+	GetCommandById(ctx context.Context, id string) (models.Command, error)
+}
+
+type LocalCommandParamsGetter interface {
+	GetCommandParams(ctx context.Context, commandId string) ([]models.CommandParameter, error)
 }
 
 func New(
 	log *slog.Logger,
 	commandGetter CommandGetter,
 	commandParametersGetter CommandParametersGetter,
-	commandScriptGetter CommandScriptGetter,
+	localCommandGetter LocalCommandGetter,
+	localParamsGetter LocalCommandParamsGetter,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "http-server.handlers.commands.get-commands"
@@ -51,15 +56,43 @@ func New(
 				log.Warn("failed to get command parameters", sl.Err(err))
 			}
 
-			commands[i].Script, err = commandScriptGetter.GetCommandScript(
-				r.Context(),
-				commands[i].ID,
-			)
+			localCommand, err := localCommandGetter.GetCommandById(r.Context(), commands[i].ID)
+			if err != nil {
+				log.Warn("failed to get local command", sl.Err(err))
+				continue
+			}
+
+			commands[i].Script = localCommand.Script
+			commands[i].TimeoutMs = localCommand.TimeoutMs
+			commands[i].Capabilities = localCommand.Capabilities
+
+			localParams, err := localParamsGetter.GetCommandParams(r.Context(), commands[i].ID)
 			if err != nil {
-				log.Warn("failed to get command script", sl.Err(err))
+				log.Warn("failed to get local command parameters", sl.Err(err))
+				continue
 			}
+			mergeConstraints(commands[i].Parameters, localParams)
 		}
 
 		render.JSON(w, r, response.OK(&commands))
 	}
 }
+
+// mergeConstraints дополняет параметры с сервера ограничениями,
+// которые хранятся только локально
+func mergeConstraints(params []models.CommandParameter, localParams []models.CommandParameter) {
+	for i := range params {
+		for _, local := range localParams {
+			if local.Name != params[i].Name {
+				continue
+			}
+			params[i].ItemType = local.ItemType
+			params[i].Required = local.Required
+			params[i].Default = local.Default
+			params[i].Min = local.Min
+			params[i].Max = local.Max
+			params[i].Enum = local.Enum
+			break
+		}
+	}
+}
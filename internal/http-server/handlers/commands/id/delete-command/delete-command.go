@@ -15,6 +15,10 @@ import (
 	"github.com/go-chi/render"
 )
 
+type LocalCommandGetter interface {
+	GetCommandById(ctx context.Context, id string) (models.Command, error)
+}
+
 type LocalCommandDeleter interface {
 	DeleteCommand(ctx context.Context, id string) (models.Command, error)
 }
@@ -23,15 +27,13 @@ type ServerCommandDeleter interface {
 	DeletePcCommand(ctx context.Context, id string) (models.Command, error)
 }
 
-type LocalCommandCreator interface {
-	CreateCommand(ctx context.Context, command models.Command) (models.Command, error)
-}
-
+// New удаляет команду сначала на сервере и только потом локально: локальное
+// удаление стирает хранилище и историю выполнения, которые нельзя вернуть
 func New(
 	log *slog.Logger,
-	localDeleter LocalCommandDeleter,
+	localGetter LocalCommandGetter,
 	serverDeleter ServerCommandDeleter,
-	localCreator LocalCommandCreator,
+	localDeleter LocalCommandDeleter,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "http-server.handlers.commands.get-commands"
@@ -44,35 +46,41 @@ func New(
 			return
 		}
 
-		deleted, err := localDeleter.DeleteCommand(r.Context(), commandID)
+		_, err := localGetter.GetCommandById(r.Context(), commandID)
 		if errors.Is(err, storage.ErrNotFound) {
 			log.Warn("command not found", sl.Err(err))
 			render.JSON(w, r, response.NotFound("command not found"))
 			return
 		}
 		if err != nil {
-			log.Error("failed to delete local command", sl.Err(err))
+			log.Error("failed to get local command", sl.Err(err))
 			render.JSON(w, r, response.InternalError())
 			return
 		}
 
-		log.Debug("local command deleted", slog.Any("deleted", deleted))
-
 		_, err = serverDeleter.DeletePcCommand(r.Context(), commandID)
-		if err == nil || errors.Is(err, services.ErrNotFound) {
-			log.Debug("command deleted from server")
-			render.JSON(w, r, response.OK(&deleted))
+		if err != nil && !errors.Is(err, services.ErrNotFound) {
+			log.Error("failed to delete command from server", sl.Err(err))
+			render.JSON(w, r, response.InternalError())
 			return
 		}
 
-		log.Error("failed to delete command from server, rollback", sl.Err(err))
+		log.Debug("command deleted from server")
 
-		_, err = localCreator.CreateCommand(r.Context(), deleted)
+		deleted, err := localDeleter.DeleteCommand(r.Context(), commandID)
+		if errors.Is(err, storage.ErrNotFound) {
+			log.Warn("command not found", sl.Err(err))
+			render.JSON(w, r, response.NotFound("command not found"))
+			return
+		}
 		if err != nil {
-			log.Error("failed to create local command", sl.Err(err))
+			log.Error("failed to delete local command", sl.Err(err))
+			render.JSON(w, r, response.InternalError())
+			return
 		}
 
-		render.JSON(w, r, response.InternalError())
-		return
+		log.Debug("local command deleted", slog.Any("deleted", deleted))
+
+		render.JSON(w, r, response.OK(&deleted))
 	}
 }
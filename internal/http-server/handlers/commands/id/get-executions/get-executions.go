@@ -0,0 +1,86 @@
+package getExecutions
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"smart-pc-agent/internal/domain/models"
+	"strconv"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/api/response"
+	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+type Response struct {
+	Executions []models.CommandExecution `json:"executions"`
+	Total      int64                     `json:"total"`
+	Limit      int64                     `json:"limit"`
+	Offset     int64                     `json:"offset"`
+}
+
+type ExecutionsGetter interface {
+	GetCommandExecutions(
+		ctx context.Context,
+		commandID string,
+		limit int64,
+		offset int64,
+	) ([]models.CommandExecution, int64, error)
+}
+
+func New(log *slog.Logger, getter ExecutionsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.commands.get-executions"
+		log := log.With(sl.Op(op), sl.ReqID(r))
+
+		commandID := chi.URLParam(r, "command_id")
+		if commandID == "" {
+			log.Warn("missing command id")
+			render.JSON(w, r, response.BadRequest("missing command id"))
+			return
+		}
+
+		limit, err := queryInt(r, "limit", defaultLimit)
+		if err != nil || limit < 1 || limit > maxLimit {
+			log.Warn("invalid limit", slog.String("limit", r.URL.Query().Get("limit")))
+			render.JSON(w, r, response.BadRequest("invalid limit"))
+			return
+		}
+
+		offset, err := queryInt(r, "offset", 0)
+		if err != nil || offset < 0 {
+			log.Warn("invalid offset", slog.String("offset", r.URL.Query().Get("offset")))
+			render.JSON(w, r, response.BadRequest("invalid offset"))
+			return
+		}
+
+		executions, total, err := getter.GetCommandExecutions(r.Context(), commandID, limit, offset)
+		if err != nil {
+			log.Error("failed to get command executions", sl.Err(err))
+			render.JSON(w, r, response.InternalError())
+			return
+		}
+
+		log.Debug("got command executions", slog.Int("count", len(executions)))
+		render.JSON(w, r, response.OK(&Response{
+			Executions: executions,
+			Total:      total,
+			Limit:      limit,
+			Offset:     offset,
+		}))
+	}
+}
+
+func queryInt(r *http.Request, name string, fallback int64) (int64, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return fallback, nil
+	}
+	return strconv.ParseInt(raw, 10, 64)
+}
@@ -0,0 +1,110 @@
+package runCommand
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"smart-pc-agent/internal/domain/models"
+	"smart-pc-agent/internal/http-server/middlewares/request"
+	scriptResponse "smart-pc-agent/internal/http-server/script-response"
+	"smart-pc-agent/internal/mqtt/commands/runner"
+	"smart-pc-agent/internal/storage"
+	"time"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/api/response"
+	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// запас ко времени выполнения скрипта на запись ответа
+const writeMargin = 5 * time.Second
+
+type Request struct {
+	Parameters map[string]string `json:"parameters,omitempty" validate:"omitempty,max=10"`
+}
+
+type CommandGetter interface {
+	GetCommandById(ctx context.Context, id string) (models.Command, error)
+}
+
+type CommandParamsGetter interface {
+	GetCommandParams(ctx context.Context, commandId string) ([]models.CommandParameter, error)
+}
+
+type ScriptRunner interface {
+	Timeout(script runner.Script) time.Duration
+	Run(ctx context.Context, script runner.Script) (runner.Result, error)
+}
+
+func New(
+	log *slog.Logger,
+	commandGetter CommandGetter,
+	paramsGetter CommandParamsGetter,
+	scriptRunner ScriptRunner,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.commands.run-command"
+		log := log.With(sl.Op(op), sl.ReqID(r))
+
+		commandID := chi.URLParam(r, "command_id")
+		if commandID == "" {
+			log.Warn("missing command id")
+			render.JSON(w, r, response.BadRequest("missing command id"))
+			return
+		}
+
+		req := request.MustGet[Request](r)
+
+		command, err := commandGetter.GetCommandById(r.Context(), commandID)
+		if errors.Is(err, storage.ErrNotFound) {
+			log.Warn("command not found")
+			render.JSON(w, r, response.NotFound("command not found"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to get command", sl.Err(err))
+			render.JSON(w, r, response.InternalError())
+			return
+		}
+
+		params, err := paramsGetter.GetCommandParams(r.Context(), commandID)
+		if err != nil {
+			log.Error("failed to get command parameters", sl.Err(err))
+			render.JSON(w, r, response.InternalError())
+			return
+		}
+
+		script := runner.Script{
+			CommandID:    command.ID,
+			Source:       command.Script,
+			Capabilities: command.Capabilities,
+			Parameters:   params,
+			Values:       req.Parameters,
+			Timeout:      time.Duration(command.TimeoutMs) * time.Millisecond,
+		}
+
+		deadline := time.Now().Add(scriptRunner.Timeout(script) + writeMargin)
+		if err := http.NewResponseController(w).SetWriteDeadline(deadline); err != nil {
+			log.Warn("failed to extend write deadline", sl.Err(err))
+		}
+
+		res, err := scriptRunner.Run(r.Context(), script)
+		scriptErr, isScriptErr := errors.AsType[*runner.Error](err)
+		if err != nil && !isScriptErr {
+			log.Error("failed to run command", sl.Err(err))
+			render.JSON(w, r, response.InternalError())
+			return
+		}
+
+		log.Debug(
+			"command run finished",
+			slog.Duration("duration", res.Duration),
+			slog.Bool("failed", isScriptErr),
+		)
+
+		run := scriptResponse.NewRun(res, scriptErr)
+		render.JSON(w, r, response.OK(&run))
+	}
+}
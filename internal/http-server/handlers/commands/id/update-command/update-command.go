@@ -3,11 +3,14 @@ package updateCommand
 import (
 	"context"
 	"errors"
-	"go/types"
 	"log/slog"
 	"net/http"
+	"slices"
 	"smart-pc-agent/internal/domain/models"
 	"smart-pc-agent/internal/http-server/middlewares/request"
+	scriptResponse "smart-pc-agent/internal/http-server/script-response"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	"smart-pc-agent/internal/mqtt/commands/runner"
 	"smart-pc-agent/internal/storage"
 
 	"github.com/MaxRomanov007/smart-pc-go-lib/api/response"
@@ -17,16 +20,33 @@ import (
 )
 
 type RequestParameter struct {
-	Name        string `json:"name"                  validate:"required,max=255"`
-	Description string `json:"omitempty,description" validate:"omitempty,max=1024"`
-	Type        int16  `json:"type"                  validate:"required,min=1,max=3"`
+	Name        string   `json:"name"                  validate:"required,max=255"`
+	Description string   `json:"omitempty,description" validate:"omitempty,max=1024"`
+	Type        int16    `json:"type"                  validate:"required,min=1,max=8"`
+	ItemType    int16    `json:"itemType,omitempty"    validate:"omitempty,oneof=2 3 4"`
+	Required    bool     `json:"required,omitempty"`
+	Default     *string  `json:"default,omitempty"     validate:"omitempty,max=1024"`
+	Min         *float64 `json:"min,omitempty"`
+	Max         *float64 `json:"max,omitempty"`
+	Enum        []string `json:"enum,omitempty"        validate:"omitempty,max=64,unique,dive,max=255"`
 }
 
 type Request struct {
-	Name        string             `json:"name"                 validate:"omitempty,max=255"`
-	Description string             `json:"description"          validate:"omitempty,max=1024"`
-	Script      string             `json:"script"               validate:"omitempty,max=8192"`
-	Parameters  []RequestParameter `json:"parameters,omitempty" validate:"omitempty,max=10,unique=Name,dive"`
+	Name         string             `json:"name"                   validate:"omitempty,max=255"`
+	Description  string             `json:"description"            validate:"omitempty,max=1024"`
+	Script       string             `json:"script"                 validate:"omitempty,max=8192"`
+	TimeoutMs    int64              `json:"timeoutMs,omitempty"    validate:"omitempty,min=0,max=3600000"`
+	Capabilities []string           `json:"capabilities,omitempty" validate:"omitempty,max=32,unique,dive,required,max=64"`
+	Parameters   []RequestParameter `json:"parameters,omitempty"   validate:"omitempty,max=10,unique=Name,dive"`
+}
+
+type Response struct {
+	Warnings []luaApi.Diagnostic `json:"warnings,omitempty"`
+}
+
+type ScriptRegistry interface {
+	Capabilities() []string
+	Schema() luaApi.APISchema
 }
 
 type ServerCommandUpdater interface {
@@ -41,6 +61,7 @@ func New(
 	log *slog.Logger,
 	localUpdater LocalCommandUpdater,
 	serverUpdater ServerCommandUpdater,
+	registry ScriptRegistry,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "http-server.handlers.commands.get-commands"
@@ -55,21 +76,56 @@ func New(
 
 		req := request.MustGet[Request](r)
 
+		known := registry.Capabilities()
+		for _, capability := range req.Capabilities {
+			if !slices.Contains(known, capability) {
+				log.Warn("unknown capability", slog.String("capability", capability))
+				render.JSON(w, r, response.BadRequest("unknown capability: "+capability))
+				return
+			}
+		}
+
 		parameters := make([]models.CommandParameter, len(req.Parameters))
+		paramNames := make([]string, len(req.Parameters))
 		for i, p := range req.Parameters {
 			parameters[i] = models.CommandParameter{
 				Name:        p.Name,
 				Description: p.Description,
 				Type:        p.Type,
+				ItemType:    p.ItemType,
+				Required:    p.Required,
+				Default:     p.Default,
+				Min:         p.Min,
+				Max:         p.Max,
+				Enum:        p.Enum,
 			}
+			paramNames[i] = p.Name
+
+			if err := runner.CheckParam(parameters[i]); err != nil {
+				log.Warn("invalid parameter", slog.String("parameter", p.Name), sl.Err(err))
+				render.JSON(w, r, response.BadRequest("parameter "+p.Name+": "+err.Error()))
+				return
+			}
+		}
+
+		diagnostics := luaApi.Check(req.Script, registry.Schema(), req.Capabilities, paramNames)
+		if luaApi.HasErrors(diagnostics) {
+			log.Warn("invalid script", slog.Any("diagnostics", diagnostics))
+			render.JSON(w, r, scriptResponse.Error(diagnostics))
+			return
+		}
+		if len(diagnostics) > 0 {
+			log.Debug("script has warnings", slog.Any("diagnostics", diagnostics))
 		}
 
 		command := models.Command{
-			ID:          commandID,
-			Name:        req.Name,
-			Description: req.Description,
-			Script:      req.Script,
-			Parameters:  parameters,
+			ID:           commandID,
+			Name:         req.Name,
+			Description:  req.Description,
+			Script:       req.Script,
+			TimeoutMs:    req.TimeoutMs,
+			Capabilities: req.Capabilities,
+			Parameters:   parameters,
 		}
 
 		updatedCommand, err := localUpdater.UpdateCommand(r.Context(), command)
@@ -94,7 +150,7 @@ func New(
 		}
 
 		log.Debug("command updated on server", slog.Any("command", updatedCommand))
-		render.JSON(w, r, response.OK[types.Nil](nil))
+		render.JSON(w, r, response.OK(&Response{Warnings: diagnostics}))
 		return
 	}
 }
@@ -0,0 +1,85 @@
+package createLibrary
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"smart-pc-agent/internal/domain/models"
+	"smart-pc-agent/internal/http-server/middlewares/request"
+	scriptResponse "smart-pc-agent/internal/http-server/script-response"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	"smart-pc-agent/internal/storage"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/api/response"
+	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
+	"github.com/go-chi/render"
+)
+
+// имя библиотеки передаётся в require, поэтому допускаются только
+// идентификаторы Lua, разделённые точками: "utils" или "net.wol"
+var namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+type Request struct {
+	Name        string `json:"name"        validate:"required,max=255"`
+	Description string `json:"description" validate:"omitempty,max=1024"`
+	Source      string `json:"source"      validate:"required,max=65536"`
+}
+
+type ScriptRegistry interface {
+	Capabilities() []string
+	Schema() luaApi.APISchema
+}
+
+type LibraryCreator interface {
+	CreateLibrary(ctx context.Context, library models.Library) (models.Library, error)
+}
+
+func New(log *slog.Logger, creator LibraryCreator, registry ScriptRegistry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.libraries.create-library"
+		log := log.With(sl.Op(op), sl.ReqID(r))
+
+		req := request.MustGet[Request](r)
+
+		if !namePattern.MatchString(req.Name) {
+			log.Warn("invalid library name", slog.String("name", req.Name))
+			render.JSON(w, r, response.BadRequest(
+				"library name must be Lua identifiers separated by dots",
+			))
+			return
+		}
+
+		// возможности и параметры зависят от команды, которая загрузит
+		// библиотеку, поэтому проверяются только синтаксис и модули spc
+		diagnostics := luaApi.Check(req.Source, registry.Schema(), registry.Capabilities(), nil)
+		if luaApi.HasErrors(diagnostics) {
+			log.Warn("invalid library source", slog.Any("diagnostics", diagnostics))
+			render.JSON(w, r, scriptResponse.Error(diagnostics))
+			return
+		}
+		if len(diagnostics) > 0 {
+			log.Debug("library source has warnings", slog.Any("diagnostics", diagnostics))
+		}
+
+		created, err := creator.CreateLibrary(r.Context(), models.Library{
+			Name:        req.Name,
+			Description: req.Description,
+			Source:      req.Source,
+		})
+		if errors.Is(err, storage.ErrAlreadyExists) {
+			log.Warn("library already exists", slog.String("name", req.Name))
+			render.JSON(w, r, response.BadRequest("library already exists"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to create library", sl.Err(err))
+			render.JSON(w, r, response.InternalError())
+			return
+		}
+
+		log.Debug("library created", slog.String("name", created.Name))
+		render.JSON(w, r, response.OK(&created))
+	}
+}
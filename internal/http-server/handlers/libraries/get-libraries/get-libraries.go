@@ -0,0 +1,32 @@
+package getLibraries
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"smart-pc-agent/internal/domain/models"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/api/response"
+	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
+	"github.com/go-chi/render"
+)
+
+type LibrariesGetter interface {
+	GetLibraries(ctx context.Context) ([]models.Library, error)
+}
+
+func New(log *slog.Logger, librariesGetter LibrariesGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.libraries.get-libraries"
+		log := log.With(sl.Op(op), sl.ReqID(r))
+
+		libraries, err := librariesGetter.GetLibraries(r.Context())
+		if err != nil {
+			log.Error("failed to get libraries", sl.Err(err))
+			render.JSON(w, r, response.InternalError())
+			return
+		}
+
+		render.JSON(w, r, response.OK(&libraries))
+	}
+}
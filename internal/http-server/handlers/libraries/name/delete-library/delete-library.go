@@ -0,0 +1,48 @@
+package deleteLibrary
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"smart-pc-agent/internal/domain/models"
+	"smart-pc-agent/internal/storage"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/api/response"
+	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type LibraryDeleter interface {
+	DeleteLibrary(ctx context.Context, name string) (models.Library, error)
+}
+
+func New(log *slog.Logger, deleter LibraryDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.libraries.delete-library"
+		log := log.With(sl.Op(op), sl.ReqID(r))
+
+		name := chi.URLParam(r, "library_name")
+		if name == "" {
+			log.Warn("missing library name")
+			render.JSON(w, r, response.BadRequest("missing library name"))
+			return
+		}
+
+		deleted, err := deleter.DeleteLibrary(r.Context(), name)
+		if errors.Is(err, storage.ErrNotFound) {
+			log.Warn("library not found", slog.String("name", name))
+			render.JSON(w, r, response.NotFound("library not found"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to delete library", sl.Err(err))
+			render.JSON(w, r, response.InternalError())
+			return
+		}
+
+		log.Debug("library deleted", slog.String("name", deleted.Name))
+		render.JSON(w, r, response.OK(&deleted))
+	}
+}
@@ -0,0 +1,83 @@
+package updateLibrary
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"smart-pc-agent/internal/domain/models"
+	"smart-pc-agent/internal/http-server/middlewares/request"
+	scriptResponse "smart-pc-agent/internal/http-server/script-response"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	"smart-pc-agent/internal/storage"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/api/response"
+	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+type Request struct {
+	Description string `json:"description" validate:"omitempty,max=1024"`
+	Source      string `json:"source"      validate:"required,max=65536"`
+}
+
+type ScriptRegistry interface {
+	Capabilities() []string
+	Schema() luaApi.APISchema
+}
+
+type LibraryUpdater interface {
+	UpdateLibrary(ctx context.Context, library models.Library) (models.Library, error)
+}
+
+func New(log *slog.Logger, updater LibraryUpdater, registry ScriptRegistry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.libraries.update-library"
+		log := log.With(sl.Op(op), sl.ReqID(r))
+
+		name := chi.URLParam(r, "library_name")
+		if name == "" {
+			log.Warn("missing library name")
+			render.JSON(w, r, response.BadRequest("missing library name"))
+			return
+		}
+
+		req := request.MustGet[Request](r)
+
+		// возможности и параметры зависят от команды, которая загрузит
+		// библиотеку, поэтому проверяются только синтаксис и модули spc
+		diagnostics := luaApi.Check(req.Source, registry.Schema(), registry.Capabilities(), nil)
+		if luaApi.HasErrors(diagnostics) {
+			log.Warn("invalid library source", slog.Any("diagnostics", diagnostics))
+			render.JSON(w, r, scriptResponse.Error(diagnostics))
+			return
+		}
+		if len(diagnostics) > 0 {
+			log.Debug("library source has warnings", slog.Any("diagnostics", diagnostics))
+		}
+
+		updated, err := updater.UpdateLibrary(r.Context(), models.Library{
+			Name:        name,
+			Description: req.Description,
+			Source:      req.Source,
+		})
+		if errors.Is(err, storage.ErrNotFound) {
+			log.Warn("library not found", slog.String("name", name))
+			render.JSON(w, r, response.NotFound("library not found"))
+			return
+		}
+		if err != nil {
+			log.Error("failed to update library", sl.Err(err))
+			render.JSON(w, r, response.InternalError())
+			return
+		}
+
+		log.Debug(
+			"library updated",
+			slog.String("name", updated.Name),
+			slog.Int64("version", updated.Version),
+		)
+		render.JSON(w, r, response.OK(&updated))
+	}
+}
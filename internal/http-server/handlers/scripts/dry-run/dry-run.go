@@ -0,0 +1,114 @@
+package dryRun
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"smart-pc-agent/internal/domain/models"
+	"smart-pc-agent/internal/http-server/middlewares/request"
+	scriptResponse "smart-pc-agent/internal/http-server/script-response"
+	"smart-pc-agent/internal/mqtt/commands/runner"
+	"time"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/api/response"
+	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
+	"github.com/go-chi/render"
+)
+
+// запас ко времени выполнения скрипта на запись ответа
+const writeMargin = 5 * time.Second
+
+// RequestParameter объявляет параметр скрипта, Value не передаётся,
+// если параметр не задан
+type RequestParameter struct {
+	Name     string   `json:"name"              validate:"required,max=255"`
+	Type     int16    `json:"type"              validate:"required,min=1,max=8"`
+	ItemType int16    `json:"itemType,omitempty" validate:"omitempty,oneof=2 3 4"`
+	Required bool     `json:"required,omitempty"`
+	Default  *string  `json:"default,omitempty" validate:"omitempty,max=1024"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+	Enum     []string `json:"enum,omitempty"    validate:"omitempty,max=64,unique,dive,max=255"`
+	Value    *string  `json:"value"             validate:"omitempty,max=1024"`
+}
+
+type Request struct {
+	Script     string             `json:"script"               validate:"required,max=8192"`
+	TimeoutMs  int64              `json:"timeoutMs,omitempty"  validate:"omitempty,min=0,max=3600000"`
+	Parameters []RequestParameter `json:"parameters,omitempty" validate:"omitempty,max=10,unique=Name,dive"`
+}
+
+type ScriptRunner interface {
+	Timeout(script runner.Script) time.Duration
+	Run(ctx context.Context, script runner.Script) (runner.Result, error)
+}
+
+// New выполняет скрипт, который не сохранён как команда, поэтому
+// spc.storage в нём недоступен, а возможности берутся из конфигурации,
+// а не из запроса
+func New(
+	log *slog.Logger,
+	scriptRunner ScriptRunner,
+	capabilities []string,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.scripts.dry-run"
+		log := log.With(sl.Op(op), sl.ReqID(r))
+
+		req := request.MustGet[Request](r)
+
+		params := make([]models.CommandParameter, len(req.Parameters))
+		values := make(map[string]string, len(req.Parameters))
+		for i, p := range req.Parameters {
+			params[i] = models.CommandParameter{
+				Name:     p.Name,
+				Type:     p.Type,
+				ItemType: p.ItemType,
+				Required: p.Required,
+				Default:  p.Default,
+				Min:      p.Min,
+				Max:      p.Max,
+				Enum:     p.Enum,
+			}
+			if err := runner.CheckParam(params[i]); err != nil {
+				log.Warn("invalid parameter", slog.String("parameter", p.Name), sl.Err(err))
+				render.JSON(w, r, response.BadRequest("parameter "+p.Name+": "+err.Error()))
+				return
+			}
+			if p.Value != nil {
+				values[p.Name] = *p.Value
+			}
+		}
+
+		script := runner.Script{
+			Source:       req.Script,
+			Capabilities: capabilities,
+			Parameters:   params,
+			Values:       values,
+			Timeout:      time.Duration(req.TimeoutMs) * time.Millisecond,
+		}
+
+		deadline := time.Now().Add(scriptRunner.Timeout(script) + writeMargin)
+		if err := http.NewResponseController(w).SetWriteDeadline(deadline); err != nil {
+			log.Warn("failed to extend write deadline", sl.Err(err))
+		}
+
+		res, err := scriptRunner.Run(r.Context(), script)
+		scriptErr, isScriptErr := errors.AsType[*runner.Error](err)
+		if err != nil && !isScriptErr {
+			log.Error("failed to run script", sl.Err(err))
+			render.JSON(w, r, response.InternalError())
+			return
+		}
+
+		log.Debug(
+			"dry run finished",
+			slog.Duration("duration", res.Duration),
+			slog.Bool("failed", isScriptErr),
+		)
+
+		run := scriptResponse.NewRun(res, scriptErr)
+		render.JSON(w, r, response.OK(&run))
+	}
+}
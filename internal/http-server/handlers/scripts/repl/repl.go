@@ -0,0 +1,253 @@
+package repl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"smart-pc-agent/internal/config"
+	localAuth "smart-pc-agent/internal/http-server/middlewares/local-auth"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	"smart-pc-agent/internal/mqtt/commands/executions"
+	"smart-pc-agent/internal/mqtt/commands/runner"
+	"sync"
+	"time"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait = 10 * time.Second
+	// скрипт не длиннее 8192 символов плюс поля сообщения
+	maxMessageSize = 16 * 1024
+	// вводы, которые ждут окончания текущего выполнения
+	queueSize = 16
+)
+
+const (
+	MessageEval   = "eval"
+	MessageCancel = "cancel"
+)
+
+const (
+	EventLog    = "log"
+	EventResult = "result"
+	EventError  = "error"
+)
+
+// Message — сообщение клиента: eval выполняет Code, cancel отменяет
+// текущее выполнение. ID возвращается в событиях этого выполнения.
+type Message struct {
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"`
+	Code string `json:"code,omitempty"`
+}
+
+// Event — сообщение сервера
+type Event struct {
+	Type       string            `json:"type"`
+	ID         string            `json:"id,omitempty"`
+	Log        *luaApi.LogEntry  `json:"log,omitempty"`
+	Values     []json.RawMessage `json:"values,omitempty"`
+	ErrorClass string            `json:"errorClass,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	DurationMs int64             `json:"durationMs,omitempty"`
+}
+
+type SessionStarter interface {
+	NewSession(ctx context.Context, capabilities []string) *runner.Session
+}
+
+// New открывает REPL-сессию с возможностями из конфигурации, сессия
+// закрывается после cfg.IdleTimeout без ввода. Соединение принимается
+// только со страниц из allowedOrigins.
+func New(
+	log *slog.Logger,
+	shutdownCtx context.Context,
+	cfg config.ScriptsREPL,
+	allowedOrigins []string,
+	capabilities []string,
+	sessionStarter SessionStarter,
+) http.HandlerFunc {
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			return localAuth.OriginAllowed(r, allowedOrigins)
+		},
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.scripts.repl"
+		log := log.With(sl.Op(op), sl.ReqID(r))
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Warn("failed to upgrade connection", sl.Err(err))
+			return
+		}
+		defer conn.Close()
+
+		ctx, cancel := context.WithCancel(shutdownCtx)
+		defer cancel()
+
+		session := sessionStarter.NewSession(ctx, capabilities)
+		defer session.Close()
+
+		log.Info("repl session started", slog.Any("capabilities", capabilities))
+
+		s := &replSession{
+			log:     log,
+			conn:    conn,
+			session: session,
+		}
+		s.serve(ctx, cfg.IdleTimeout)
+
+		log.Info("repl session finished")
+	}
+}
+
+type replSession struct {
+	log     *slog.Logger
+	conn    *websocket.Conn
+	session *runner.Session
+
+	mu         sync.Mutex
+	cancelEval context.CancelCauseFunc
+}
+
+func (s *replSession) serve(ctx context.Context, idleTimeout time.Duration) {
+	messages := make(chan Message, queueSize)
+	readErr := make(chan error, 1)
+	go s.read(ctx, idleTimeout, messages, readErr)
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.close(websocket.CloseGoingAway, "agent is shutting down")
+			return
+		case err := <-readErr:
+			if netErr, ok := errors.AsType[net.Error](err); ok && netErr.Timeout() {
+				s.close(websocket.CloseNormalClosure, "idle timeout")
+				return
+			}
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				s.log.Debug("repl connection closed", sl.Err(err))
+			}
+			return
+		case msg := <-messages:
+			if err := s.eval(ctx, msg); err != nil {
+				s.log.Debug("failed to write repl event", sl.Err(err))
+				return
+			}
+		}
+	}
+}
+
+// read читает сообщения клиента; cancel обрабатывается сразу, чтобы
+// прервать выполнение, которое занимает основной цикл
+func (s *replSession) read(
+	ctx context.Context,
+	idleTimeout time.Duration,
+	messages chan<- Message,
+	readErr chan<- error,
+) {
+	s.conn.SetReadLimit(maxMessageSize)
+
+	for {
+		if err := s.conn.SetReadDeadline(time.Now().Add(idleTimeout)); err != nil {
+			readErr <- err
+			return
+		}
+
+		var msg Message
+		if err := s.conn.ReadJSON(&msg); err != nil {
+			// клиент ушёл, результат текущего выполнения уже некому отправить
+			s.cancelCurrent(err)
+			readErr <- err
+			return
+		}
+
+		switch msg.Type {
+		case MessageCancel:
+			s.cancelCurrent(executions.ErrCancelled)
+		case MessageEval:
+			select {
+			case messages <- msg:
+			case <-ctx.Done():
+				return
+			}
+		default:
+			s.log.Debug("unknown repl message", slog.String("type", msg.Type))
+		}
+	}
+}
+
+func (s *replSession) cancelCurrent(cause error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancelEval != nil {
+		s.cancelEval(cause)
+	}
+}
+
+func (s *replSession) eval(ctx context.Context, msg Message) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	s.mu.Lock()
+	s.cancelEval = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.cancelEval = nil
+		s.mu.Unlock()
+	}()
+
+	var writeErr error
+	res, err := s.session.Eval(ctx, msg.Code, func(entry luaApi.LogEntry) {
+		if writeErr == nil {
+			writeErr = s.write(Event{Type: EventLog, ID: msg.ID, Log: &entry})
+		}
+	})
+	if writeErr != nil {
+		return writeErr
+	}
+
+	if scriptErr, ok := errors.AsType[*runner.Error](err); ok {
+		return s.write(Event{
+			Type:       EventError,
+			ID:         msg.ID,
+			ErrorClass: scriptErr.Class,
+			Error:      scriptErr.Message,
+			DurationMs: res.Duration.Milliseconds(),
+		})
+	}
+	if err != nil {
+		s.log.Error("failed to evaluate repl input", sl.Err(err))
+		return s.write(Event{Type: EventError, ID: msg.ID, Error: "internal error"})
+	}
+
+	return s.write(Event{
+		Type:       EventResult,
+		ID:         msg.ID,
+		Values:     res.Values,
+		DurationMs: res.Duration.Milliseconds(),
+	})
+}
+
+func (s *replSession) write(event Event) error {
+	if err := s.conn.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
+		return err
+	}
+	return s.conn.WriteJSON(event)
+}
+
+func (s *replSession) close(code int, reason string) {
+	_ = s.conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(writeWait),
+	)
+}
@@ -6,19 +6,33 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"smart-pc-agent/internal/config"
 	"smart-pc-agent/internal/http-server/handlers/api/schema"
+	"smart-pc-agent/internal/http-server/handlers/api/schema/luals"
 	createCommand "smart-pc-agent/internal/http-server/handlers/commands/create-command"
 	getCommands "smart-pc-agent/internal/http-server/handlers/commands/get-commands"
 	deleteCommand "smart-pc-agent/internal/http-server/handlers/commands/id/delete-command"
+	getExecutions "smart-pc-agent/internal/http-server/handlers/commands/id/get-executions"
+	runCommand "smart-pc-agent/internal/http-server/handlers/commands/id/run-command"
 	updateCommand "smart-pc-agent/internal/http-server/handlers/commands/id/update-command"
 	deleteThisPc "smart-pc-agent/internal/http-server/handlers/delete-this-pc"
 	"smart-pc-agent/internal/http-server/handlers/health/stream"
+	createLibrary "smart-pc-agent/internal/http-server/handlers/libraries/create-library"
+	getLibraries "smart-pc-agent/internal/http-server/handlers/libraries/get-libraries"
+	deleteLibrary "smart-pc-agent/internal/http-server/handlers/libraries/name/delete-library"
+	getLibrary "smart-pc-agent/internal/http-server/handlers/libraries/name/get-library"
+	updateLibrary "smart-pc-agent/internal/http-server/handlers/libraries/name/update-library"
 	pcId "smart-pc-agent/internal/http-server/handlers/pc-id"
+	dryRun "smart-pc-agent/internal/http-server/handlers/scripts/dry-run"
+	"smart-pc-agent/internal/http-server/handlers/scripts/repl"
+	localAuth "smart-pc-agent/internal/http-server/middlewares/local-auth"
 	"smart-pc-agent/internal/http-server/middlewares/request"
 	luaApi "smart-pc-agent/internal/lib/lua-api"
+	"smart-pc-agent/internal/mqtt/commands/runner"
 	pcsService "smart-pc-agent/internal/services/pcs-service"
 	"smart-pc-agent/internal/storage/sqlite"
+	"strings"
 
 	mwLogger "smart-pc-agent/internal/http-server/middlewares/logger"
 
@@ -41,9 +55,12 @@ func New(
 	ctx context.Context,
 	log *slog.Logger,
 	cfg config.HTTPServer,
+	scriptsCfg config.Scripts,
+	token string,
 	storage *sqlite.Storage,
 	service *pcsService.Service,
 	registry *luaApi.Registry,
+	scriptRunner *runner.Runner,
 	stopApp func(),
 ) *Server {
 	r := chi.NewRouter()
@@ -52,7 +69,11 @@ func New(
 		middleware.Recoverer,
 		mwLogger.New(log),
 		cors.Handler(cors.Options{
-			AllowedOrigins: []string{"*"},
+			AllowOriginFunc: func(r *http.Request, origin string) bool {
+				return slices.ContainsFunc(cfg.AllowedOrigins, func(allowed string) bool {
+					return strings.EqualFold(origin, allowed)
+				})
+			},
 			AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
 			AllowedHeaders: []string{"*"},
 			MaxAge:         300,
@@ -60,15 +81,18 @@ func New(
 	)
 
 	v := validator.New()
+	// выполнение скриптов доступно только локальному интерфейсу с токеном
+	scriptAuth := localAuth.New(log, token, cfg.AllowedOrigins)
+	jsonOnly := middleware.AllowContentType("application/json")
 
 	r.Get("/pc-id", pcId.New(log, storage.AppStorage))
 	r.Get("/health/stream", stream.New(log, ctx))
 	r.Get(
 		"/commands",
-		getCommands.New(log, service, service, storage.Commands),
+		getCommands.New(log, service, service, storage.Commands, storage.CommandParameters),
 	)
 	r.With(request.New[createCommand.Request](log, v)).
-		Post("/commands", createCommand.New(log, service, service, storage.Commands))
+		Post("/commands", createCommand.New(log, service, service, storage.Commands, registry))
 
 	r.Delete(
 		"/commands/{command_id}",
@@ -77,12 +101,49 @@ func New(
 
 	r.With(request.New[updateCommand.Request](log, v)).Patch(
 		"/commands/{command_id}",
-		updateCommand.New(log, storage.Commands, service),
+		updateCommand.New(log, storage.Commands, service, registry),
 	)
 
+	r.Get(
+		"/commands/{command_id}/executions",
+		getExecutions.New(log, storage.CommandExecutions),
+	)
+
+	r.With(scriptAuth, jsonOnly, request.New[runCommand.Request](log, v)).Post(
+		"/commands/{command_id}/run",
+		runCommand.New(log, storage.Commands, storage.CommandParameters, scriptRunner),
+	)
+
+	r.With(scriptAuth, jsonOnly, request.New[dryRun.Request](log, v)).Post(
+		"/scripts/dry-run",
+		dryRun.New(log, scriptRunner, scriptsCfg.LocalCapabilities),
+	)
+	r.With(scriptAuth).Get("/scripts/repl", repl.New(
+		log,
+		ctx,
+		scriptsCfg.REPL,
+		cfg.AllowedOrigins,
+		scriptsCfg.LocalCapabilities,
+		scriptRunner,
+	))
+
+	r.Get("/libraries", getLibraries.New(log, storage.Libraries))
+	r.With(request.New[createLibrary.Request](log, v)).
+		Post("/libraries", createLibrary.New(log, storage.Libraries, registry))
+	r.Get("/libraries/{library_name}", getLibrary.New(log, storage.Libraries))
+	r.With(request.New[updateLibrary.Request](log, v)).Patch(
+		"/libraries/{library_name}",
+		updateLibrary.New(log, storage.Libraries, registry),
+	)
+	r.Delete("/libraries/{library_name}", deleteLibrary.New(log, storage.Libraries))
+
 	r.Delete("/", deleteThisPc.New(log, storage.AppStorage, service, storage, stopApp))
 
-	r.Get("/api/schema", schema.New(log, registry))
+	r.Get("/api/schema", schema.New(log, registry, storage.Libraries))
+	r.Get(
+		"/api/schema/luals",
+		luals.New(log, registry, storage.Commands, storage.CommandParameters),
+	)
 
 	srv := &http.Server{
 		Addr:         cfg.Address,
@@ -0,0 +1,73 @@
+package localAuth
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/api/response"
+	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
+	"github.com/go-chi/render"
+)
+
+// New пропускает только запросы с токеном установки и из разрешённых
+// источников, чтобы сторонние страницы не могли выполнять скрипты
+func New(
+	log *slog.Logger,
+	token string,
+	allowedOrigins []string,
+) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			const op = "middlewares.local-auth"
+			log := log.With(sl.Op(op), sl.ReqID(r))
+
+			if !OriginAllowed(r, allowedOrigins) {
+				log.Warn("origin is not allowed", slog.String("origin", r.Header.Get("Origin")))
+				render.Status(r, http.StatusForbidden)
+				render.JSON(w, r, response.BadRequest("origin is not allowed"))
+				return
+			}
+
+			got := requestToken(r)
+			if got == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				log.Warn("invalid token")
+				render.Status(r, http.StatusUnauthorized)
+				render.JSON(w, r, response.BadRequest("invalid token"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// OriginAllowed разрешает запросы без Origin (не из браузера) и запросы
+// со страниц из списка
+func OriginAllowed(r *http.Request, allowedOrigins []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	for _, allowed := range allowedOrigins {
+		if strings.EqualFold(origin, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestToken берёт токен из заголовка Authorization, а для WebSocket,
+// где браузер не даёт задать заголовки, из параметра token
+func requestToken(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return r.URL.Query().Get("token")
+	}
+	return ""
+}
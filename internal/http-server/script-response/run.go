@@ -0,0 +1,35 @@
+package scriptResponse
+
+import (
+	"encoding/json"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	"smart-pc-agent/internal/mqtt/commands/runner"
+)
+
+// Run — результат тестового запуска скрипта. Ошибка скрипта не считается
+// ошибкой запроса и возвращается в полях ErrorClass и Error.
+type Run struct {
+	Result      json.RawMessage   `json:"result,omitempty"`
+	Logs        []luaApi.LogEntry `json:"logs"`
+	DroppedLogs int               `json:"droppedLogs,omitempty"`
+	ErrorClass  string            `json:"errorClass,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	DurationMs  int64             `json:"durationMs"`
+}
+
+func NewRun(res runner.Result, scriptErr *runner.Error) Run {
+	run := Run{
+		Result:      res.Value,
+		Logs:        res.Logs,
+		DroppedLogs: res.DroppedLogs,
+		DurationMs:  res.Duration.Milliseconds(),
+	}
+	if run.Logs == nil {
+		run.Logs = []luaApi.LogEntry{}
+	}
+	if scriptErr != nil {
+		run.ErrorClass = scriptErr.Class
+		run.Error = scriptErr.Message
+	}
+	return run
+}
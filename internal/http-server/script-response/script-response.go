@@ -0,0 +1,31 @@
+package scriptResponse
+
+import (
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	"strings"
+)
+
+const StatusError = "Error"
+
+// Response повторяет формат ошибок валидации и дополнительно содержит
+// диагностики скрипта с номерами строк и колонок
+type Response struct {
+	Status      string              `json:"status"`
+	Error       string              `json:"error"`
+	Diagnostics []luaApi.Diagnostic `json:"diagnostics"`
+}
+
+func Error(diagnostics []luaApi.Diagnostic) Response {
+	messages := make([]string, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		if d.Severity == luaApi.SeverityError {
+			messages = append(messages, "script: "+d.String())
+		}
+	}
+
+	return Response{
+		Status:      StatusError,
+		Error:       strings.Join(messages, ", "),
+		Diagnostics: diagnostics,
+	}
+}
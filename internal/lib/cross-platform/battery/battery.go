@@ -0,0 +1,18 @@
+// Package battery reports the state of the system battery.
+package battery
+
+import "errors"
+
+// ErrNotPresent is returned when the system has no battery.
+var ErrNotPresent = errors.New("battery: not present")
+
+// Status describes the battery charge.
+type Status struct {
+	Percent  float64 // Charge level from 0 to 100
+	Charging bool    // Connected to power and charging
+}
+
+// Get returns the current battery status or ErrNotPresent.
+func Get() (Status, error) {
+	return get()
+}
@@ -0,0 +1,39 @@
+//go:build darwin
+
+package battery
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// On macOS we parse the output of `pmset -g batt`, e.g.
+//  -InternalBattery-0 (id=1234)	87%; charging; 1:02 remaining present: true
+
+var percentPattern = regexp.MustCompile(`(\d+)%;\s*([a-zA-Z ]+);`)
+
+func get() (Status, error) {
+	out, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return Status{}, fmt.Errorf("battery: pmset: %w", err)
+	}
+
+	match := percentPattern.FindStringSubmatch(string(out))
+	if match == nil {
+		return Status{}, ErrNotPresent
+	}
+
+	percent, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return Status{}, fmt.Errorf("battery: invalid percent %q: %w", match[1], err)
+	}
+
+	state := strings.TrimSpace(match[2])
+	return Status{
+		Percent:  percent,
+		Charging: state == "charging" || state == "charged",
+	}, nil
+}
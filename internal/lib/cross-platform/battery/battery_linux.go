@@ -0,0 +1,51 @@
+//go:build linux
+
+package battery
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// On Linux batteries are exposed by the kernel under /sys/class/power_supply.
+// Only supplies of type "Battery" are taken into account, the first one wins.
+
+const powerSupplyPath = "/sys/class/power_supply"
+
+func get() (Status, error) {
+	supplies, err := os.ReadDir(powerSupplyPath)
+	if err != nil {
+		return Status{}, ErrNotPresent
+	}
+
+	for _, supply := range supplies {
+		dir := filepath.Join(powerSupplyPath, supply.Name())
+		if readValue(dir, "type") != "Battery" {
+			continue
+		}
+
+		capacity, err := strconv.ParseFloat(readValue(dir, "capacity"), 64)
+		if err != nil {
+			return Status{}, fmt.Errorf("battery: invalid capacity of %s: %w", supply.Name(), err)
+		}
+
+		status := readValue(dir, "status")
+		return Status{
+			Percent:  capacity,
+			Charging: status == "Charging" || status == "Full",
+		}, nil
+	}
+
+	return Status{}, ErrNotPresent
+}
+
+func readValue(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,7 @@
+//go:build !windows && !darwin && !linux
+
+package battery
+
+func get() (Status, error) {
+	return Status{}, ErrNotPresent
+}
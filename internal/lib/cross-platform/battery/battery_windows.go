@@ -0,0 +1,52 @@
+//go:build windows
+
+package battery
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// SYSTEM_POWER_STATUS structure
+// https://learn.microsoft.com/en-us/windows/win32/api/winbase/ns-winbase-system_power_status
+type systemPowerStatus struct {
+	ACLineStatus        byte
+	BatteryFlag         byte
+	BatteryLifePercent  byte
+	SystemStatusFlag    byte
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+const (
+	batteryFlagNoBattery  = 128
+	batteryFlagUnknown    = 255
+	batteryFlagCharging   = 8
+	batteryPercentUnknown = 255
+)
+
+var (
+	kernel32             = syscall.NewLazyDLL("kernel32.dll")
+	getSystemPowerStatus = kernel32.NewProc("GetSystemPowerStatus")
+)
+
+func get() (Status, error) {
+	var status systemPowerStatus
+
+	ret, _, err := getSystemPowerStatus.Call(uintptr(unsafe.Pointer(&status)))
+	if ret == 0 {
+		return Status{}, fmt.Errorf("battery: GetSystemPowerStatus: %w", err)
+	}
+
+	if status.BatteryFlag == batteryFlagUnknown ||
+		status.BatteryFlag&batteryFlagNoBattery != 0 ||
+		status.BatteryLifePercent == batteryPercentUnknown {
+		return Status{}, ErrNotPresent
+	}
+
+	return Status{
+		Percent:  float64(status.BatteryLifePercent),
+		Charging: status.BatteryFlag&batteryFlagCharging != 0,
+	}, nil
+}
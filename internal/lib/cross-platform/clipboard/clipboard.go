@@ -0,0 +1,13 @@
+// Package clipboard provides cross-platform access to the system clipboard text.
+package clipboard
+
+// Read returns the current text content of the clipboard.
+// An empty string is returned when the clipboard holds no text.
+func Read() (string, error) {
+	return read()
+}
+
+// Write replaces the clipboard content with text.
+func Write(text string) error {
+	return write(text)
+}
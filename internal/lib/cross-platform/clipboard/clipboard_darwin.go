@@ -0,0 +1,28 @@
+//go:build darwin
+
+package clipboard
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// On macOS we use the pbcopy / pbpaste utilities shipped with the system.
+
+func read() (string, error) {
+	out, err := exec.Command("pbpaste").Output()
+	if err != nil {
+		return "", fmt.Errorf("clipboard: pbpaste: %w", err)
+	}
+	return string(out), nil
+}
+
+func write(text string) error {
+	cmd := exec.Command("pbcopy")
+	cmd.Stdin = strings.NewReader(text)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("clipboard: pbcopy: %w — %s", err, out)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+//go:build linux
+
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// On Linux we prefer wl-clipboard (wl-copy / wl-paste) on Wayland.
+// If it is not installed or there is no Wayland session we fall back
+// to xclip (X11 CLIPBOARD selection).
+
+func read() (string, error) {
+	text, wlErr := readViaWlPaste()
+	if wlErr == nil {
+		return text, nil
+	}
+
+	text, xErr := readViaXclip()
+	if xErr == nil {
+		return text, nil
+	}
+	return "", errors.Join(wlErr, xErr)
+}
+
+func write(text string) error {
+	wlErr := writeViaWlCopy(text)
+	if wlErr == nil {
+		return nil
+	}
+
+	xErr := writeViaXclip(text)
+	if xErr == nil {
+		return nil
+	}
+	return errors.Join(wlErr, xErr)
+}
+
+func readViaWlPaste() (string, error) {
+	path, err := exec.LookPath("wl-paste")
+	if err != nil {
+		return "", fmt.Errorf("clipboard: wl-paste not found: %w", err)
+	}
+
+	var stderr strings.Builder
+	cmd := exec.Command(path, "--no-newline", "--type", "text")
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		// wl-paste fails when the clipboard is empty
+		if strings.Contains(stderr.String(), "Nothing is copied") {
+			return "", nil
+		}
+		return "", fmt.Errorf("clipboard: wl-paste: %w — %s", err, stderr.String())
+	}
+	return string(out), nil
+}
+
+func readViaXclip() (string, error) {
+	path, err := exec.LookPath("xclip")
+	if err != nil {
+		return "", fmt.Errorf("clipboard: xclip not found: %w", err)
+	}
+
+	var stderr strings.Builder
+	cmd := exec.Command(path, "-selection", "clipboard", "-out")
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("clipboard: xclip -out: %w — %s", err, stderr.String())
+	}
+	return string(out), nil
+}
+
+// Both wl-copy and xclip fork a background process that serves the
+// selection, so their output is not captured: the child keeps the pipes
+// open and reading them would block until the clipboard changes.
+
+func writeViaWlCopy(text string) error {
+	path, err := exec.LookPath("wl-copy")
+	if err != nil {
+		return fmt.Errorf("clipboard: wl-copy not found: %w", err)
+	}
+
+	cmd := exec.Command(path, "--type", "text/plain")
+	cmd.Stdin = strings.NewReader(text)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("clipboard: wl-copy: %w", err)
+	}
+	return nil
+}
+
+func writeViaXclip(text string) error {
+	path, err := exec.LookPath("xclip")
+	if err != nil {
+		return fmt.Errorf("clipboard: neither wl-copy nor xclip found; install one of them")
+	}
+
+	cmd := exec.Command(path, "-selection", "clipboard", "-in")
+	cmd.Stdin = strings.NewReader(text)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("clipboard: xclip -in: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !windows && !darwin && !linux
+
+package clipboard
+
+import "fmt"
+
+func read() (string, error) {
+	return "", fmt.Errorf("clipboard: unsupported platform")
+}
+
+func write(text string) error {
+	return fmt.Errorf("clipboard: unsupported platform")
+}
@@ -0,0 +1,137 @@
+//go:build windows
+
+package clipboard
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// On Windows we use the Win32 clipboard API with CF_UNICODETEXT.
+// https://learn.microsoft.com/en-us/windows/win32/dataxchg/using-the-clipboard
+
+const (
+	cfUnicodeText = 13
+	gmemMoveable  = 0x0002
+
+	// the clipboard can be briefly held open by another application
+	openAttempts = 10
+	openDelay    = 20 * time.Millisecond
+)
+
+var (
+	user32                     = syscall.NewLazyDLL("user32.dll")
+	openClipboard              = user32.NewProc("OpenClipboard")
+	closeClipboard             = user32.NewProc("CloseClipboard")
+	emptyClipboard             = user32.NewProc("EmptyClipboard")
+	getClipboardData           = user32.NewProc("GetClipboardData")
+	setClipboardData           = user32.NewProc("SetClipboardData")
+	isClipboardFormatAvailable = user32.NewProc("IsClipboardFormatAvailable")
+
+	kernel32     = syscall.NewLazyDLL("kernel32.dll")
+	globalAlloc  = kernel32.NewProc("GlobalAlloc")
+	globalFree   = kernel32.NewProc("GlobalFree")
+	globalLock   = kernel32.NewProc("GlobalLock")
+	globalUnlock = kernel32.NewProc("GlobalUnlock")
+)
+
+func read() (string, error) {
+	// the clipboard is owned by the thread that opened it
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if err := open(); err != nil {
+		return "", err
+	}
+	defer closeClipboard.Call()
+
+	if ok, _, _ := isClipboardFormatAvailable.Call(cfUnicodeText); ok == 0 {
+		return "", nil
+	}
+
+	handle, _, err := getClipboardData.Call(cfUnicodeText)
+	if handle == 0 {
+		return "", fmt.Errorf("clipboard: GetClipboardData: %w", err)
+	}
+
+	ptr, _, err := globalLock.Call(handle)
+	if ptr == 0 {
+		return "", fmt.Errorf("clipboard: GlobalLock: %w", err)
+	}
+	defer globalUnlock.Call(handle)
+
+	return utf16PtrToString(ptr), nil
+}
+
+func write(text string) error {
+	data, err := syscall.UTF16FromString(text)
+	if err != nil {
+		return fmt.Errorf("clipboard: invalid text: %w", err)
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if err := open(); err != nil {
+		return err
+	}
+	defer closeClipboard.Call()
+
+	if ok, _, err := emptyClipboard.Call(); ok == 0 {
+		return fmt.Errorf("clipboard: EmptyClipboard: %w", err)
+	}
+
+	size := uintptr(len(data)) * unsafe.Sizeof(data[0])
+	handle, _, err := globalAlloc.Call(gmemMoveable, size)
+	if handle == 0 {
+		return fmt.Errorf("clipboard: GlobalAlloc: %w", err)
+	}
+
+	ptr, _, err := globalLock.Call(handle)
+	if ptr == 0 {
+		globalFree.Call(handle)
+		return fmt.Errorf("clipboard: GlobalLock: %w", err)
+	}
+	copy(unsafe.Slice((*uint16)(toPointer(ptr)), len(data)), data)
+	globalUnlock.Call(handle)
+
+	// on success the system owns the memory
+	if ok, _, err := setClipboardData.Call(cfUnicodeText, handle); ok == 0 {
+		globalFree.Call(handle)
+		return fmt.Errorf("clipboard: SetClipboardData: %w", err)
+	}
+	return nil
+}
+
+func open() error {
+	var err error
+	for range openAttempts {
+		var ok uintptr
+		if ok, _, err = openClipboard.Call(0); ok != 0 {
+			return nil
+		}
+		time.Sleep(openDelay)
+	}
+	return fmt.Errorf("clipboard: OpenClipboard: %w", err)
+}
+
+// utf16PtrToString reads a NUL-terminated UTF-16 string from memory
+// returned by GlobalLock.
+func utf16PtrToString(ptr uintptr) string {
+	start := toPointer(ptr)
+
+	n := 0
+	for *(*uint16)(unsafe.Add(start, n*2)) != 0 {
+		n++
+	}
+	return syscall.UTF16ToString(unsafe.Slice((*uint16)(start), n))
+}
+
+// toPointer converts memory returned by the Win32 API, which is not
+// managed by the Go runtime, to unsafe.Pointer.
+func toPointer(ptr uintptr) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&ptr))
+}
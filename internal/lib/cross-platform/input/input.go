@@ -0,0 +1,164 @@
+// Package input synthesizes keyboard and mouse events using native OS facilities.
+package input
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Button is a mouse button.
+type Button int
+
+const (
+	Left Button = iota
+	Right
+	Middle
+)
+
+var (
+	// ErrUnknownKey is returned for key names that can not be pressed.
+	ErrUnknownKey = errors.New("input: unknown key")
+	// ErrUnknownButton is returned for unsupported mouse button names.
+	ErrUnknownButton = errors.New("input: unknown mouse button")
+)
+
+// Key names accepted in combinations besides single characters.
+var namedKeys = map[string]bool{
+	"ctrl": true, "shift": true, "alt": true, "super": true,
+	"enter": true, "tab": true, "escape": true, "space": true,
+	"backspace": true, "delete": true, "insert": true,
+	"home": true, "end": true, "pageup": true, "pagedown": true,
+	"up": true, "down": true, "left": true, "right": true,
+	"f1": true, "f2": true, "f3": true, "f4": true, "f5": true, "f6": true,
+	"f7": true, "f8": true, "f9": true, "f10": true, "f11": true, "f12": true,
+}
+
+var keyAliases = map[string]string{
+	"control": "ctrl",
+	"option":  "alt",
+	"win":     "super",
+	"meta":    "super",
+	"cmd":     "super",
+	"command": "super",
+	"return":  "enter",
+	"esc":     "escape",
+	"del":     "delete",
+	"ins":     "insert",
+	"pgup":    "pageup",
+	"pgdn":    "pagedown",
+	"plus":    "+",
+	"minus":   "-",
+}
+
+// All functions stop synthesizing events once ctx is done. On Linux the
+// helper tool is killed, elsewhere events are posted directly and ctx is
+// checked before posting.
+
+// KeyTap presses a key combination such as "ctrl+shift+t" or "f5".
+// Keys are pressed in order and released in reverse order.
+func KeyTap(ctx context.Context, combo string) error {
+	keys, err := parseCombo(combo)
+	if err != nil {
+		return err
+	}
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+	return keyTap(ctx, keys)
+}
+
+// Type types text as if it was entered from the keyboard.
+func Type(ctx context.Context, text string) error {
+	if text == "" {
+		return nil
+	}
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+	return typeText(ctx, text)
+}
+
+// MouseMove moves the cursor to absolute screen coordinates.
+func MouseMove(ctx context.Context, x, y int) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+	return mouseMove(ctx, x, y)
+}
+
+// MouseMoveRelative moves the cursor by the given offset.
+func MouseMoveRelative(ctx context.Context, dx, dy int) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+	return mouseMoveRelative(ctx, dx, dy)
+}
+
+// Click clicks the mouse button count times at the current position.
+func Click(ctx context.Context, button Button, count int) error {
+	if count < 1 {
+		count = 1
+	}
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+	return click(ctx, button, count)
+}
+
+// Scroll scrolls the mouse wheel by the given number of steps.
+// Positive dy scrolls down, positive dx scrolls right.
+func Scroll(ctx context.Context, dx, dy int) error {
+	if dx == 0 && dy == 0 {
+		return nil
+	}
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+	return scroll(ctx, dx, dy)
+}
+
+func checkContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("input: %w", err)
+	}
+	return nil
+}
+
+// ParseButton converts "left", "right" or "middle" to Button.
+// An empty string means Left.
+func ParseButton(s string) (Button, error) {
+	switch strings.ToLower(s) {
+	case "", "left":
+		return Left, nil
+	case "right":
+		return Right, nil
+	case "middle":
+		return Middle, nil
+	default:
+		return Left, fmt.Errorf("%w: %q", ErrUnknownButton, s)
+	}
+}
+
+// parseCombo splits a combination into normalized key names:
+// named keys from namedKeys or single lowercase characters.
+func parseCombo(combo string) ([]string, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(combo)), "+")
+	keys := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		key := strings.TrimSpace(part)
+		if alias, ok := keyAliases[key]; ok {
+			key = alias
+		}
+
+		if !namedKeys[key] && utf8.RuneCountInString(key) != 1 {
+			return nil, fmt.Errorf("%w: %q in %q", ErrUnknownKey, part, combo)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
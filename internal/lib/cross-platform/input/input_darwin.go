@@ -0,0 +1,182 @@
+//go:build darwin
+
+package input
+
+/*
+#cgo LDFLAGS: -framework CoreFoundation -framework CoreGraphics
+
+#include <CoreFoundation/CoreFoundation.h>
+#include <CoreGraphics/CoreGraphics.h>
+
+static void postKey(CGKeyCode code, bool down, CGEventFlags flags) {
+    CGEventRef event = CGEventCreateKeyboardEvent(NULL, code, down);
+    CGEventSetFlags(event, flags);
+    CGEventPost(kCGHIDEventTap, event);
+    CFRelease(event);
+}
+
+// Types up to two UTF-16 units (one character) regardless of the layout
+static void postUnicode(UniChar *chars, int length) {
+    CGEventRef down = CGEventCreateKeyboardEvent(NULL, 0, true);
+    CGEventKeyboardSetUnicodeString(down, length, chars);
+    CGEventPost(kCGHIDEventTap, down);
+    CFRelease(down);
+
+    CGEventRef up = CGEventCreateKeyboardEvent(NULL, 0, false);
+    CGEventKeyboardSetUnicodeString(up, length, chars);
+    CGEventPost(kCGHIDEventTap, up);
+    CFRelease(up);
+}
+
+static CGPoint cursorPosition(void) {
+    CGEventRef event = CGEventCreate(NULL);
+    CGPoint point = CGEventGetLocation(event);
+    CFRelease(event);
+    return point;
+}
+
+static void moveMouse(double x, double y) {
+    CGEventRef event = CGEventCreateMouseEvent(
+        NULL, kCGEventMouseMoved, CGPointMake(x, y), kCGMouseButtonLeft);
+    CGEventPost(kCGHIDEventTap, event);
+    CFRelease(event);
+}
+
+static void moveMouseRelative(double dx, double dy) {
+    CGPoint point = cursorPosition();
+    moveMouse(point.x + dx, point.y + dy);
+}
+
+// button: 0 - left, 1 - right, 2 - middle
+static void clickMouse(int button, int count) {
+    CGEventType downType = kCGEventLeftMouseDown;
+    CGEventType upType = kCGEventLeftMouseUp;
+    CGMouseButton mouseButton = kCGMouseButtonLeft;
+    if (button == 1) {
+        downType = kCGEventRightMouseDown;
+        upType = kCGEventRightMouseUp;
+        mouseButton = kCGMouseButtonRight;
+    } else if (button == 2) {
+        downType = kCGEventOtherMouseDown;
+        upType = kCGEventOtherMouseUp;
+        mouseButton = kCGMouseButtonCenter;
+    }
+
+    CGPoint point = cursorPosition();
+    for (int i = 1; i <= count; i++) {
+        // click state makes consecutive clicks a double / triple click
+        CGEventRef down = CGEventCreateMouseEvent(NULL, downType, point, mouseButton);
+        CGEventSetIntegerValueField(down, kCGMouseEventClickState, i);
+        CGEventPost(kCGHIDEventTap, down);
+        CFRelease(down);
+
+        CGEventRef up = CGEventCreateMouseEvent(NULL, upType, point, mouseButton);
+        CGEventSetIntegerValueField(up, kCGMouseEventClickState, i);
+        CGEventPost(kCGHIDEventTap, up);
+        CFRelease(up);
+    }
+}
+
+// Positive wheel values scroll up and left
+static void scrollWheel(int vertical, int horizontal) {
+    CGEventRef event = CGEventCreateScrollWheelEvent2(
+        NULL, kCGScrollEventUnitLine, 2, vertical, horizontal, 0);
+    CGEventPost(kCGHIDEventTap, event);
+    CFRelease(event);
+}
+*/
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf16"
+)
+
+// On macOS we post Quartz events. The agent needs the Accessibility
+// permission (System Settings → Privacy & Security → Accessibility),
+// otherwise the events are silently dropped by the system.
+
+// Virtual key codes of the ANSI layout (HIToolbox/Events.h)
+var keyCodes = map[string]C.CGKeyCode{
+	"ctrl": 59, "shift": 56, "alt": 58, "super": 55,
+	"enter": 36, "tab": 48, "escape": 53, "space": 49,
+	"backspace": 51, "delete": 117, "insert": 114,
+	"home": 115, "end": 119, "pageup": 116, "pagedown": 121,
+	"up": 126, "down": 125, "left": 123, "right": 124,
+	"f1": 122, "f2": 120, "f3": 99, "f4": 118, "f5": 96, "f6": 97,
+	"f7": 98, "f8": 100, "f9": 101, "f10": 109, "f11": 103, "f12": 111,
+	"a": 0, "s": 1, "d": 2, "f": 3, "h": 4, "g": 5, "z": 6, "x": 7, "c": 8, "v": 9,
+	"b": 11, "q": 12, "w": 13, "e": 14, "r": 15, "y": 16, "t": 17,
+	"1": 18, "2": 19, "3": 20, "4": 21, "6": 22, "5": 23, "=": 24, "9": 25, "7": 26,
+	"-": 27, "8": 28, "0": 29, "]": 30, "o": 31, "u": 32, "[": 33, "i": 34, "p": 35,
+	"l": 37, "j": 38, "'": 39, "k": 40, ";": 41, "\\": 42, ",": 43, "/": 44,
+	"n": 45, "m": 46, ".": 47, "`": 50,
+}
+
+var modifierFlags = map[string]C.CGEventFlags{
+	"ctrl":  C.kCGEventFlagMaskControl,
+	"shift": C.kCGEventFlagMaskShift,
+	"alt":   C.kCGEventFlagMaskAlternate,
+	"super": C.kCGEventFlagMaskCommand,
+}
+
+func keyTap(ctx context.Context, keys []string) error {
+	codes := make([]C.CGKeyCode, 0, len(keys))
+	for _, key := range keys {
+		code, ok := keyCodes[key]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownKey, key)
+		}
+		codes = append(codes, code)
+	}
+
+	// modifier state has to be set on every event, applications read
+	// the flags instead of tracking modifier key presses
+	var flags C.CGEventFlags
+	for i, key := range keys {
+		flags |= modifierFlags[key]
+		C.postKey(codes[i], true, flags)
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		flags &^= modifierFlags[keys[i]]
+		C.postKey(codes[i], false, flags)
+	}
+
+	return nil
+}
+
+func typeText(ctx context.Context, text string) error {
+	for _, r := range text {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("input: %w", err)
+		}
+		units := utf16.Encode([]rune{r})
+		chars := make([]C.UniChar, len(units))
+		for i, unit := range units {
+			chars[i] = C.UniChar(unit)
+		}
+		C.postUnicode(&chars[0], C.int(len(chars)))
+	}
+	return nil
+}
+
+func mouseMove(ctx context.Context, x, y int) error {
+	C.moveMouse(C.double(x), C.double(y))
+	return nil
+}
+
+func mouseMoveRelative(ctx context.Context, dx, dy int) error {
+	C.moveMouseRelative(C.double(dx), C.double(dy))
+	return nil
+}
+
+func click(ctx context.Context, button Button, count int) error {
+	C.clickMouse(C.int(button), C.int(count))
+	return nil
+}
+
+func scroll(ctx context.Context, dx, dy int) error {
+	C.scrollWheel(C.int(-dy), C.int(-dx))
+	return nil
+}
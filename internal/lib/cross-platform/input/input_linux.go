@@ -0,0 +1,212 @@
+//go:build linux
+
+package input
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// On Linux we use xdotool on X11 and ydotool on Wayland, where X11 tools
+// can not reach native windows. ydotool writes events to /dev/uinput through
+// its ydotoold daemon, so it works in any session, but needs the daemon
+// running and access to uinput. Whichever tool suits the session is tried
+// first, the other one is the fallback.
+
+func run(actions func(tool string) error) error {
+	tools := []string{"xdotool", "ydotool"}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		tools = []string{"ydotool", "xdotool"}
+	}
+
+	errs := make([]error, 0, len(tools))
+	for _, tool := range tools {
+		if _, err := exec.LookPath(tool); err != nil {
+			continue
+		}
+		err := actions(tool)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return fmt.Errorf("input: neither xdotool nor ydotool found; install one of them")
+	}
+	return errors.Join(errs...)
+}
+
+// waitDelay bounds how long command waits for output after the tool is
+// killed, in case it left children holding the pipe
+const waitDelay = time.Second
+
+// command is killed when ctx is done, so a long type or click does not
+// outlive the script that started it
+func command(ctx context.Context, tool string, args ...string) error {
+	cmd := exec.CommandContext(ctx, tool, args...)
+	cmd.WaitDelay = waitDelay
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("input: %s %s: %w", tool, args[0], ctxErr)
+		}
+		return fmt.Errorf("input: %s %s: %w — %s", tool, args[0], err, out)
+	}
+	return nil
+}
+
+func keyTap(ctx context.Context, keys []string) error {
+	return run(func(tool string) error {
+		if tool == "ydotool" {
+			return ydotoolKeyTap(ctx, keys)
+		}
+		return xdotoolKeyTap(ctx, keys)
+	})
+}
+
+func typeText(ctx context.Context, text string) error {
+	return run(func(tool string) error {
+		return command(ctx, tool, "type", "--", text)
+	})
+}
+
+func mouseMove(ctx context.Context, x, y int) error {
+	return run(func(tool string) error {
+		if tool == "ydotool" {
+			return command(ctx, tool, "mousemove", "--absolute", "-x", strconv.Itoa(x), "-y", strconv.Itoa(y))
+		}
+		return command(ctx, tool, "mousemove", "--", strconv.Itoa(x), strconv.Itoa(y))
+	})
+}
+
+func mouseMoveRelative(ctx context.Context, dx, dy int) error {
+	return run(func(tool string) error {
+		if tool == "ydotool" {
+			return command(ctx, tool, "mousemove", "-x", strconv.Itoa(dx), "-y", strconv.Itoa(dy))
+		}
+		return command(ctx, tool, "mousemove_relative", "--", strconv.Itoa(dx), strconv.Itoa(dy))
+	})
+}
+
+func click(ctx context.Context, button Button, count int) error {
+	return run(func(tool string) error {
+		if tool == "ydotool" {
+			// 0xC0 | button: press and release
+			code := map[Button]string{Left: "0xC0", Right: "0xC1", Middle: "0xC2"}[button]
+			return command(ctx, tool, "click", "--repeat", strconv.Itoa(count), code)
+		}
+		code := map[Button]string{Left: "1", Right: "3", Middle: "2"}[button]
+		return command(ctx, tool, "click", "--repeat", strconv.Itoa(count), code)
+	})
+}
+
+func scroll(ctx context.Context, dx, dy int) error {
+	return run(func(tool string) error {
+		if tool == "ydotool" {
+			// wheel axes follow evdev: positive y scrolls up
+			return command(ctx, tool, "mousemove", "--wheel", "-x", strconv.Itoa(dx), "-y", strconv.Itoa(-dy))
+		}
+
+		// X11 has no wheel events, scrolling is clicking buttons 4-7
+		steps := []struct {
+			amount   int
+			negative string
+			positive string
+		}{
+			{dy, "4", "5"},
+			{dx, "6", "7"},
+		}
+		for _, step := range steps {
+			if step.amount == 0 {
+				continue
+			}
+			button, repeat := step.positive, step.amount
+			if repeat < 0 {
+				button, repeat = step.negative, -repeat
+			}
+			if err := command(ctx, tool, "click", "--repeat", strconv.Itoa(repeat), button); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// xdotool keysym names, letters and digits are passed as is
+var xdotoolKeys = map[string]string{
+	"ctrl": "ctrl", "shift": "shift", "alt": "alt", "super": "super",
+	"enter": "Return", "tab": "Tab", "escape": "Escape", "space": "space",
+	"backspace": "BackSpace", "delete": "Delete", "insert": "Insert",
+	"home": "Home", "end": "End", "pageup": "Prior", "pagedown": "Next",
+	"up": "Up", "down": "Down", "left": "Left", "right": "Right",
+	"f1": "F1", "f2": "F2", "f3": "F3", "f4": "F4", "f5": "F5", "f6": "F6",
+	"f7": "F7", "f8": "F8", "f9": "F9", "f10": "F10", "f11": "F11", "f12": "F12",
+	"-": "minus", "=": "equal", "+": "plus", ",": "comma", ".": "period",
+	"/": "slash", "\\": "backslash", ";": "semicolon", "'": "apostrophe",
+	"[": "bracketleft", "]": "bracketright", "`": "grave",
+}
+
+func xdotoolKeyTap(ctx context.Context, keys []string) error {
+	combo := ""
+	for i, key := range keys {
+		name, ok := xdotoolKeys[key]
+		if !ok {
+			if !isAlphanumeric(key) {
+				return fmt.Errorf("%w: %q", ErrUnknownKey, key)
+			}
+			name = key
+		}
+		if i > 0 {
+			combo += "+"
+		}
+		combo += name
+	}
+	return command(ctx, "xdotool", "key", "--clearmodifiers", combo)
+}
+
+// Linux input event codes (linux/input-event-codes.h), ydotool sends raw codes
+var ydotoolKeys = map[string]int{
+	"ctrl": 29, "shift": 42, "alt": 56, "super": 125,
+	"enter": 28, "tab": 15, "escape": 1, "space": 57,
+	"backspace": 14, "delete": 111, "insert": 110,
+	"home": 102, "end": 107, "pageup": 104, "pagedown": 109,
+	"up": 103, "down": 108, "left": 105, "right": 106,
+	"f1": 59, "f2": 60, "f3": 61, "f4": 62, "f5": 63, "f6": 64,
+	"f7": 65, "f8": 66, "f9": 67, "f10": 68, "f11": 87, "f12": 88,
+	"1": 2, "2": 3, "3": 4, "4": 5, "5": 6, "6": 7, "7": 8, "8": 9, "9": 10, "0": 11,
+	"q": 16, "w": 17, "e": 18, "r": 19, "t": 20, "y": 21, "u": 22, "i": 23, "o": 24, "p": 25,
+	"a": 30, "s": 31, "d": 32, "f": 33, "g": 34, "h": 35, "j": 36, "k": 37, "l": 38,
+	"z": 44, "x": 45, "c": 46, "v": 47, "b": 48, "n": 49, "m": 50,
+	"-": 12, "=": 13, "[": 26, "]": 27, ";": 39, "'": 40, "`": 41,
+	"\\": 43, ",": 51, ".": 52, "/": 53,
+}
+
+func ydotoolKeyTap(ctx context.Context, keys []string) error {
+	args := make([]string, 0, len(keys)*2+1)
+	args = append(args, "key")
+
+	codes := make([]int, 0, len(keys))
+	for _, key := range keys {
+		code, ok := ydotoolKeys[key]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownKey, key)
+		}
+		codes = append(codes, code)
+		args = append(args, fmt.Sprintf("%d:1", code))
+	}
+	for i := len(codes) - 1; i >= 0; i-- {
+		args = append(args, fmt.Sprintf("%d:0", codes[i]))
+	}
+
+	return command(ctx, "ydotool", args...)
+}
+
+func isAlphanumeric(key string) bool {
+	c := key[0]
+	return len(key) == 1 && (c >= 'a' && c <= 'z' || c >= '0' && c <= '9')
+}
@@ -0,0 +1,34 @@
+//go:build !windows && !darwin && !linux
+
+package input
+
+import (
+	"context"
+	"fmt"
+)
+
+var errUnsupported = fmt.Errorf("input: unsupported platform")
+
+func keyTap(ctx context.Context, keys []string) error {
+	return errUnsupported
+}
+
+func typeText(ctx context.Context, text string) error {
+	return errUnsupported
+}
+
+func mouseMove(ctx context.Context, x, y int) error {
+	return errUnsupported
+}
+
+func mouseMoveRelative(ctx context.Context, dx, dy int) error {
+	return errUnsupported
+}
+
+func click(ctx context.Context, button Button, count int) error {
+	return errUnsupported
+}
+
+func scroll(ctx context.Context, dx, dy int) error {
+	return errUnsupported
+}
@@ -0,0 +1,205 @@
+//go:build windows
+
+package input
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+)
+
+// On Windows we use SendInput, which also supports typing arbitrary
+// Unicode text with KEYEVENTF_UNICODE regardless of the keyboard layout.
+// https://learn.microsoft.com/en-us/windows/win32/api/winuser/nf-winuser-sendinput
+
+const (
+	inputMouse    = 0
+	inputKeyboard = 1
+
+	keyeventfKeyup   = 0x0002
+	keyeventfUnicode = 0x0004
+
+	mouseeventfLeftDown   = 0x0002
+	mouseeventfLeftUp     = 0x0004
+	mouseeventfRightDown  = 0x0008
+	mouseeventfRightUp    = 0x0010
+	mouseeventfMiddleDown = 0x0020
+	mouseeventfMiddleUp   = 0x0040
+	mouseeventfWheel      = 0x0800
+	mouseeventfHWheel     = 0x1000
+
+	wheelDelta = 120
+)
+
+// Virtual key codes
+// https://learn.microsoft.com/en-us/windows/win32/inputdev/virtual-key-codes
+var virtualKeys = map[string]uint16{
+	"ctrl": 0x11, "shift": 0x10, "alt": 0x12, "super": 0x5B,
+	"enter": 0x0D, "tab": 0x09, "escape": 0x1B, "space": 0x20,
+	"backspace": 0x08, "delete": 0x2E, "insert": 0x2D,
+	"home": 0x24, "end": 0x23, "pageup": 0x21, "pagedown": 0x22,
+	"up": 0x26, "down": 0x28, "left": 0x25, "right": 0x27,
+	"f1": 0x70, "f2": 0x71, "f3": 0x72, "f4": 0x73, "f5": 0x74, "f6": 0x75,
+	"f7": 0x76, "f8": 0x77, "f9": 0x78, "f10": 0x79, "f11": 0x7A, "f12": 0x7B,
+}
+
+// KEYBDINPUT and MOUSEINPUT wrapped into INPUT. The union in INPUT is as
+// large as MOUSEINPUT, so keyboard input is padded to the same size.
+type keybdInput struct {
+	vk        uint16
+	scan      uint16
+	flags     uint32
+	time      uint32
+	extraInfo uintptr
+}
+
+type mouseInput struct {
+	dx        int32
+	dy        int32
+	mouseData uint32
+	flags     uint32
+	time      uint32
+	extraInfo uintptr
+}
+
+type keyboardEvent struct {
+	typ     uint32
+	ki      keybdInput
+	padding [8]byte
+}
+
+type mouseEvent struct {
+	typ uint32
+	mi  mouseInput
+}
+
+var (
+	user32       = syscall.NewLazyDLL("user32.dll")
+	sendInput    = user32.NewProc("SendInput")
+	setCursorPos = user32.NewProc("SetCursorPos")
+	getCursorPos = user32.NewProc("GetCursorPos")
+	vkKeyScanW   = user32.NewProc("VkKeyScanW")
+)
+
+func keyTap(ctx context.Context, keys []string) error {
+	codes := make([]uint16, 0, len(keys))
+	for _, key := range keys {
+		vk, err := virtualKey(key)
+		if err != nil {
+			return err
+		}
+		codes = append(codes, vk)
+	}
+
+	events := make([]keyboardEvent, 0, len(codes)*2)
+	for _, vk := range codes {
+		events = append(events, keyboardEvent{typ: inputKeyboard, ki: keybdInput{vk: vk}})
+	}
+	for i := len(codes) - 1; i >= 0; i-- {
+		events = append(events, keyboardEvent{
+			typ: inputKeyboard,
+			ki:  keybdInput{vk: codes[i], flags: keyeventfKeyup},
+		})
+	}
+
+	return send(unsafe.Pointer(&events[0]), len(events), unsafe.Sizeof(events[0]))
+}
+
+func typeText(ctx context.Context, text string) error {
+	units := utf16.Encode([]rune(text))
+
+	events := make([]keyboardEvent, 0, len(units)*2)
+	for _, unit := range units {
+		events = append(events,
+			keyboardEvent{typ: inputKeyboard, ki: keybdInput{scan: unit, flags: keyeventfUnicode}},
+			keyboardEvent{
+				typ: inputKeyboard,
+				ki:  keybdInput{scan: unit, flags: keyeventfUnicode | keyeventfKeyup},
+			},
+		)
+	}
+
+	return send(unsafe.Pointer(&events[0]), len(events), unsafe.Sizeof(events[0]))
+}
+
+func mouseMove(ctx context.Context, x, y int) error {
+	if ok, _, err := setCursorPos.Call(uintptr(x), uintptr(y)); ok == 0 {
+		return fmt.Errorf("input: SetCursorPos: %w", err)
+	}
+	return nil
+}
+
+func mouseMoveRelative(ctx context.Context, dx, dy int) error {
+	var point struct{ x, y int32 }
+	if ok, _, err := getCursorPos.Call(uintptr(unsafe.Pointer(&point))); ok == 0 {
+		return fmt.Errorf("input: GetCursorPos: %w", err)
+	}
+	return mouseMove(ctx, int(point.x)+dx, int(point.y)+dy)
+}
+
+func click(ctx context.Context, button Button, count int) error {
+	flags := map[Button][2]uint32{
+		Left:   {mouseeventfLeftDown, mouseeventfLeftUp},
+		Right:  {mouseeventfRightDown, mouseeventfRightUp},
+		Middle: {mouseeventfMiddleDown, mouseeventfMiddleUp},
+	}[button]
+
+	events := make([]mouseEvent, 0, count*2)
+	for range count {
+		events = append(events,
+			mouseEvent{typ: inputMouse, mi: mouseInput{flags: flags[0]}},
+			mouseEvent{typ: inputMouse, mi: mouseInput{flags: flags[1]}},
+		)
+	}
+
+	return send(unsafe.Pointer(&events[0]), len(events), unsafe.Sizeof(events[0]))
+}
+
+func scroll(ctx context.Context, dx, dy int) error {
+	events := make([]mouseEvent, 0, 2)
+	if dy != 0 {
+		// positive wheel delta scrolls up
+		events = append(events, mouseEvent{
+			typ: inputMouse,
+			mi:  mouseInput{flags: mouseeventfWheel, mouseData: uint32(int32(-dy * wheelDelta))},
+		})
+	}
+	if dx != 0 {
+		events = append(events, mouseEvent{
+			typ: inputMouse,
+			mi:  mouseInput{flags: mouseeventfHWheel, mouseData: uint32(int32(dx * wheelDelta))},
+		})
+	}
+
+	return send(unsafe.Pointer(&events[0]), len(events), unsafe.Sizeof(events[0]))
+}
+
+func send(events unsafe.Pointer, count int, size uintptr) error {
+	sent, _, err := sendInput.Call(uintptr(count), uintptr(events), size)
+	if int(sent) != count {
+		return fmt.Errorf("input: SendInput: %w", err)
+	}
+	return nil
+}
+
+// virtualKey maps a key name to a virtual key code, single characters are
+// resolved with VkKeyScanW for the current keyboard layout.
+func virtualKey(key string) (uint16, error) {
+	if vk, ok := virtualKeys[key]; ok {
+		return vk, nil
+	}
+
+	runes := []rune(key)
+	if len(runes) != 1 || runes[0] > 0xFFFF {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownKey, key)
+	}
+
+	// low byte is the key code, high byte the shift state; -1 if not on the layout
+	ret, _, _ := vkKeyScanW.Call(uintptr(runes[0]))
+	if int16(ret) == -1 {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownKey, key)
+	}
+	return uint16(ret & 0xFF), nil
+}
@@ -0,0 +1,51 @@
+// Package notify shows desktop notifications using native OS facilities.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Urgency is the importance level of a notification.
+type Urgency byte
+
+const (
+	Low      Urgency = iota // Informational, may be shown silently
+	Normal                  // Default level
+	Critical                // Should stay visible until dismissed
+)
+
+// ErrEmptyTitle is returned when a notification has no title.
+var ErrEmptyTitle = errors.New("notify: title is empty")
+
+// Notification describes a desktop notification.
+type Notification struct {
+	Title   string
+	Body    string
+	Urgency Urgency
+	Icon    string // Icon name or absolute path to an image, optional
+}
+
+// Send shows the notification on the desktop of the current user session.
+func Send(n Notification) error {
+	if n.Title == "" {
+		return ErrEmptyTitle
+	}
+	return send(n)
+}
+
+// ParseUrgency converts "low", "normal" or "critical" to Urgency.
+// An empty string means Normal.
+func ParseUrgency(s string) (Urgency, error) {
+	switch strings.ToLower(s) {
+	case "low":
+		return Low, nil
+	case "", "normal":
+		return Normal, nil
+	case "critical":
+		return Critical, nil
+	default:
+		return Normal, fmt.Errorf("notify: unknown urgency %q", s)
+	}
+}
@@ -0,0 +1,36 @@
+//go:build darwin
+
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// On macOS we use AppleScript `display notification`.
+// Text is passed as script arguments instead of being interpolated
+// into the script source. Custom icons are not supported by
+// AppleScript, the icon of the calling app is always shown.
+
+var notifyScript = []string{
+	"-e", "on run argv",
+	"-e", "if item 3 of argv is \"sound\" then",
+	"-e", "display notification (item 2 of argv) with title (item 1 of argv) sound name \"default\"",
+	"-e", "else",
+	"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+	"-e", "end if",
+	"-e", "end run",
+}
+
+func send(n Notification) error {
+	sound := ""
+	if n.Urgency == Critical {
+		sound = "sound"
+	}
+
+	args := append(append([]string{}, notifyScript...), n.Title, n.Body, sound)
+	if out, err := exec.Command("osascript", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("notify: osascript: %w — %s", err, out)
+	}
+	return nil
+}
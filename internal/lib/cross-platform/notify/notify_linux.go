@@ -0,0 +1,60 @@
+//go:build linux
+
+package notify
+
+import (
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// On Linux we speak the org.freedesktop.Notifications D-Bus interface
+// directly, it is implemented by every major desktop environment
+// (GNOME Shell, KDE Plasma, dunst, mako, xfce4-notifyd, ...).
+// https://specifications.freedesktop.org/notification-spec/latest/
+
+const (
+	notificationsName = "org.freedesktop.Notifications"
+	notificationsPath = "/org/freedesktop/Notifications"
+	notifyMethod      = notificationsName + ".Notify"
+
+	appName = "Smart PC"
+
+	// -1 lets the notification server choose the timeout
+	defaultExpireTimeout = int32(-1)
+)
+
+func send(n Notification) error {
+	// The session bus connection is shared and must not be closed.
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return fmt.Errorf("notify: failed to connect to session bus: %w", err)
+	}
+	return sendViaDBus(conn, n)
+}
+
+// sendViaDBus calls Notify on the given bus, so any connection providing
+// org.freedesktop.Notifications (e.g. a private test bus) can be used.
+func sendViaDBus(conn *dbus.Conn, n Notification) error {
+	hints := map[string]dbus.Variant{
+		"urgency": dbus.MakeVariant(byte(n.Urgency)),
+	}
+
+	var id uint32
+	err := conn.Object(notificationsName, notificationsPath).Call(
+		notifyMethod,
+		0,
+		appName,
+		uint32(0), // replaces_id: always show a new notification
+		n.Icon,
+		n.Title,
+		n.Body,
+		[]string{}, // actions
+		hints,
+		defaultExpireTimeout,
+	).Store(&id)
+	if err != nil {
+		return fmt.Errorf("notify: %s: %w", notifyMethod, err)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+//go:build linux
+
+package notify
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// busConfig is a minimal session bus that lets any connection own
+// any name, enough to stand in for the desktop notification server.
+const busConfig = `<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:dir=%DIR%</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*"/>
+    <allow receive_sender="*"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`
+
+// notifyCall holds the arguments of one Notify call.
+type notifyCall struct {
+	AppName    string
+	ReplacesID uint32
+	Icon       string
+	Summary    string
+	Body       string
+	Actions    []string
+	Hints      map[string]dbus.Variant
+	Timeout    int32
+}
+
+// fakeServer implements org.freedesktop.Notifications.Notify.
+type fakeServer struct {
+	mu    sync.Mutex
+	calls []notifyCall
+}
+
+func (s *fakeServer) Notify(
+	appName string,
+	replacesID uint32,
+	icon string,
+	summary string,
+	body string,
+	actions []string,
+	hints map[string]dbus.Variant,
+	timeout int32,
+) (uint32, *dbus.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls = append(s.calls, notifyCall{
+		AppName:    appName,
+		ReplacesID: replacesID,
+		Icon:       icon,
+		Summary:    summary,
+		Body:       body,
+		Actions:    actions,
+		Hints:      hints,
+		Timeout:    timeout,
+	})
+	return uint32(len(s.calls)), nil
+}
+
+func (s *fakeServer) Calls() []notifyCall {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]notifyCall(nil), s.calls...)
+}
+
+// startBus runs a private dbus-daemon and returns its address.
+func startBus(t *testing.T) string {
+	t.Helper()
+
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon is not installed")
+	}
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "bus.conf")
+	config := strings.ReplaceAll(busConfig, "%DIR%", dir)
+	if err := os.WriteFile(configPath, []byte(config), 0o600); err != nil {
+		t.Fatalf("failed to write bus config: %v", err)
+	}
+
+	cmd := exec.Command(daemon, "--config-file="+configPath, "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("failed to get dbus-daemon stdout: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("failed to read bus address: %v", err)
+	}
+	return strings.TrimSpace(address)
+}
+
+func connect(t *testing.T, address string) *dbus.Conn {
+	t.Helper()
+
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatalf("failed to connect to bus: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestSendViaDBus(t *testing.T) {
+	address := startBus(t)
+
+	server := &fakeServer{}
+	serverConn := connect(t, address)
+	if err := serverConn.Export(server, notificationsPath, notificationsName); err != nil {
+		t.Fatalf("failed to export fake server: %v", err)
+	}
+	reply, err := serverConn.RequestName(notificationsName, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("failed to own %s: reply %v, error %v", notificationsName, reply, err)
+	}
+
+	clientConn := connect(t, address)
+
+	tests := []Notification{
+		{Title: "Backup", Body: "42 files copied", Urgency: Normal, Icon: "dialog-information"},
+		{Title: "Disk is full", Urgency: Critical, Icon: "/usr/share/icons/disk.png"},
+		{Title: "Low", Body: "quiet", Urgency: Low},
+	}
+
+	for _, n := range tests {
+		if err := sendViaDBus(clientConn, n); err != nil {
+			t.Fatalf("sendViaDBus(%+v) failed: %v", n, err)
+		}
+	}
+
+	calls := server.Calls()
+	if len(calls) != len(tests) {
+		t.Fatalf("got %d Notify calls, want %d", len(calls), len(tests))
+	}
+
+	for i, n := range tests {
+		call := calls[i]
+
+		if call.AppName != appName {
+			t.Errorf("call %d: app name = %q, want %q", i, call.AppName, appName)
+		}
+		if call.ReplacesID != 0 {
+			t.Errorf("call %d: replaces id = %d, want 0", i, call.ReplacesID)
+		}
+		if call.Summary != n.Title {
+			t.Errorf("call %d: summary = %q, want %q", i, call.Summary, n.Title)
+		}
+		if call.Body != n.Body {
+			t.Errorf("call %d: body = %q, want %q", i, call.Body, n.Body)
+		}
+		if call.Icon != n.Icon {
+			t.Errorf("call %d: icon = %q, want %q", i, call.Icon, n.Icon)
+		}
+		if call.Timeout != defaultExpireTimeout {
+			t.Errorf("call %d: timeout = %d, want %d", i, call.Timeout, defaultExpireTimeout)
+		}
+		if len(call.Actions) != 0 {
+			t.Errorf("call %d: actions = %v, want none", i, call.Actions)
+		}
+
+		urgency, ok := call.Hints["urgency"].Value().(byte)
+		if !ok || urgency != byte(n.Urgency) {
+			t.Errorf("call %d: urgency hint = %v, want %d", i, call.Hints["urgency"], n.Urgency)
+		}
+	}
+}
+
+func TestSendViaDBusWithoutServer(t *testing.T) {
+	address := startBus(t)
+	conn := connect(t, address)
+
+	err := sendViaDBus(conn, Notification{Title: "nobody listens"})
+	if err == nil {
+		t.Fatal("expected an error when no notification server is running")
+	}
+	if !strings.Contains(err.Error(), notifyMethod) {
+		t.Errorf("error %q does not name the method", err)
+	}
+}
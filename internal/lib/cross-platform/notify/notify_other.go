@@ -0,0 +1,9 @@
+//go:build !windows && !darwin && !linux
+
+package notify
+
+import "fmt"
+
+func send(n Notification) error {
+	return fmt.Errorf("notify: unsupported platform")
+}
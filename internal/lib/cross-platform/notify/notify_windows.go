@@ -0,0 +1,89 @@
+//go:build windows
+
+package notify
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+)
+
+// On Windows we show a toast via the WinRT ToastNotificationManager.
+// It is reached through PowerShell to avoid cgo / COM bindings.
+// The toast XML is passed through an environment variable, so user
+// supplied text never becomes a part of the script.
+// https://learn.microsoft.com/en-us/windows/apps/design/shell/tiles-and-notifications/adaptive-interactive-toasts
+
+// AUMID of PowerShell, toasts from unregistered app ids are silently dropped.
+const appID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+const toastScript = `
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+[Windows.Data.Xml.Dom.XmlDocument, Windows.Data.Xml.Dom.XmlDocument, ContentType = WindowsRuntime] | Out-Null
+$xml = New-Object Windows.Data.Xml.Dom.XmlDocument
+$xml.LoadXml($env:SPC_TOAST_XML)
+$toast = New-Object Windows.UI.Notifications.ToastNotification $xml
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($env:SPC_TOAST_APP_ID).Show($toast)
+`
+
+const createNoWindow = 0x08000000
+
+func send(n Notification) error {
+	toast, err := toastXML(n)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("powershell.exe", "-NoProfile", "-NonInteractive", "-Command", toastScript)
+	cmd.Env = append(os.Environ(), "SPC_TOAST_XML="+toast, "SPC_TOAST_APP_ID="+appID)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true, CreationFlags: createNoWindow}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("notify: powershell toast: %w — %s", err, out)
+	}
+	return nil
+}
+
+func toastXML(n Notification) (string, error) {
+	var buf bytes.Buffer
+
+	scenario := ""
+	if n.Urgency == Critical {
+		// "urgent" keeps the toast on screen (Windows 11), older versions ignore it
+		scenario = ` scenario="urgent"`
+	}
+	buf.WriteString(`<toast` + scenario + `><visual><binding template="ToastGeneric">`)
+
+	if n.Icon != "" && filepath.IsAbs(n.Icon) {
+		src := (&url.URL{Scheme: "file", Path: "/" + filepath.ToSlash(n.Icon)}).String()
+		buf.WriteString(`<image placement="appLogoOverride" src="`)
+		if err := xml.EscapeText(&buf, []byte(src)); err != nil {
+			return "", fmt.Errorf("notify: failed to escape icon: %w", err)
+		}
+		buf.WriteString(`"/>`)
+	}
+
+	for _, text := range []string{n.Title, n.Body} {
+		if text == "" {
+			continue
+		}
+		buf.WriteString(`<text>`)
+		if err := xml.EscapeText(&buf, []byte(text)); err != nil {
+			return "", fmt.Errorf("notify: failed to escape text: %w", err)
+		}
+		buf.WriteString(`</text>`)
+	}
+
+	buf.WriteString(`</binding></visual>`)
+	if n.Urgency == Low {
+		buf.WriteString(`<audio silent="true"/>`)
+	}
+	buf.WriteString(`</toast>`)
+
+	return buf.String(), nil
+}
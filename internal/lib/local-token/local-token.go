@@ -0,0 +1,47 @@
+package localToken
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const tokenSize = 32
+
+// Load возвращает токен установки из файла, при первом запуске токен
+// генерируется и сохраняется с доступом только для владельца
+func Load(path string) (string, error) {
+	const op = "lib.local-token.Load"
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("%s: token file %s is empty", op, path)
+		}
+		return token, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("%s: failed to read token: %w", op, err)
+	}
+
+	raw := make([]byte, tokenSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("%s: failed to generate token: %w", op, err)
+	}
+	token := hex.EncodeToString(raw)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", fmt.Errorf("%s: failed to create token directory: %w", op, err)
+	}
+	if err := os.WriteFile(path, []byte(token), 0o600); err != nil {
+		return "", fmt.Errorf("%s: failed to save token: %w", op, err)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,294 @@
+package luaApi
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/ast"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Diagnostic описывает ошибку или предупреждение в скрипте,
+// Column равен 0, если колонка неизвестна
+type Diagnostic struct {
+	Severity string `json:"severity"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	if d.Column > 0 {
+		return fmt.Sprintf("line %d, column %d: %s", d.Line, d.Column, d.Message)
+	}
+	return fmt.Sprintf("line %d: %s", d.Line, d.Message)
+}
+
+// HasErrors сообщает, есть ли среди диагностик ошибки
+func HasErrors(diagnostics []Diagnostic) bool {
+	return slices.ContainsFunc(diagnostics, func(d Diagnostic) bool {
+		return d.Severity == SeverityError
+	})
+}
+
+// Check компилирует скрипт и проверяет обращения к spc.* по схеме.
+// Синтаксические ошибки возвращаются с уровнем error, обращения к
+// несуществующим модулям, функциям, недоступным возможностям и
+// необъявленным параметрам — с уровнем warning.
+func Check(script string, schema APISchema, capabilities []string, params []string) []Diagnostic {
+	chunk, err := parse.Parse(strings.NewReader(script), "script")
+	if err != nil {
+		return []Diagnostic{parseDiagnostic(script, err)}
+	}
+	if _, err := lua.Compile(chunk, "script"); err != nil {
+		return []Diagnostic{compileDiagnostic(err)}
+	}
+
+	c := &checker{
+		schema:       schema,
+		capabilities: capabilities,
+		params:       params,
+		missing:      make(map[string]bool),
+	}
+	c.stmts(chunk)
+	return c.diagnostics
+}
+
+func parseDiagnostic(script string, err error) Diagnostic {
+	parseErr, ok := errors.AsType[*parse.Error](err)
+	if !ok {
+		return Diagnostic{Severity: SeverityError, Line: 1, Message: err.Error()}
+	}
+
+	d := Diagnostic{
+		Severity: SeverityError,
+		Line:     parseErr.Pos.Line,
+		Column:   parseErr.Pos.Column,
+		Message:  parseErr.Message,
+	}
+	if parseErr.Token != "" {
+		d.Message = fmt.Sprintf("%s near '%s'", parseErr.Message, parseErr.Token)
+	}
+	if d.Line == parse.EOF {
+		d.Line = strings.Count(script, "\n") + 1
+		d.Column = 0
+		d.Message = parseErr.Message + " at end of script"
+	}
+	return d
+}
+
+func compileDiagnostic(err error) Diagnostic {
+	compileErr, ok := errors.AsType[*lua.CompileError](err)
+	if !ok {
+		return Diagnostic{Severity: SeverityError, Line: 1, Message: err.Error()}
+	}
+	return Diagnostic{
+		Severity: SeverityError,
+		Line:     compileErr.Line,
+		Message:  compileErr.Message,
+	}
+}
+
+// checker обходит AST и собирает предупреждения об обращениях к spc.*.
+// Области видимости не отслеживаются: после объявления переменной или
+// параметра с именем spc проверка отключается до конца скрипта.
+type checker struct {
+	schema       APISchema
+	capabilities []string
+	params       []string
+	shadowed     bool
+	// возможности, о которых уже предупредили
+	missing     map[string]bool
+	diagnostics []Diagnostic
+}
+
+func (c *checker) warn(line int, format string, args ...any) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{
+		Severity: SeverityWarning,
+		Line:     line,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *checker) declare(names ...string) {
+	if slices.Contains(names, "spc") {
+		c.shadowed = true
+	}
+}
+
+func (c *checker) stmts(stmts []ast.Stmt) {
+	for _, stmt := range stmts {
+		c.stmt(stmt)
+	}
+}
+
+func (c *checker) stmt(stmt ast.Stmt) {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		for _, lhs := range s.Lhs {
+			if ident, ok := lhs.(*ast.IdentExpr); ok {
+				c.declare(ident.Value)
+			}
+		}
+		c.exprs(s.Lhs)
+		c.exprs(s.Rhs)
+	case *ast.LocalAssignStmt:
+		c.exprs(s.Exprs)
+		c.declare(s.Names...)
+	case *ast.FuncCallStmt:
+		c.expr(s.Expr)
+	case *ast.DoBlockStmt:
+		c.stmts(s.Stmts)
+	case *ast.WhileStmt:
+		c.expr(s.Condition)
+		c.stmts(s.Stmts)
+	case *ast.RepeatStmt:
+		c.stmts(s.Stmts)
+		c.expr(s.Condition)
+	case *ast.IfStmt:
+		c.expr(s.Condition)
+		c.stmts(s.Then)
+		c.stmts(s.Else)
+	case *ast.NumberForStmt:
+		c.expr(s.Init)
+		c.expr(s.Limit)
+		c.expr(s.Step)
+		c.declare(s.Name)
+		c.stmts(s.Stmts)
+	case *ast.GenericForStmt:
+		c.exprs(s.Exprs)
+		c.declare(s.Names...)
+		c.stmts(s.Stmts)
+	case *ast.FuncDefStmt:
+		if s.Name.Func != nil {
+			if ident, ok := s.Name.Func.(*ast.IdentExpr); ok {
+				c.declare(ident.Value)
+			}
+			c.expr(s.Name.Func)
+		}
+		c.expr(s.Name.Receiver)
+		c.expr(s.Func)
+	case *ast.ReturnStmt:
+		c.exprs(s.Exprs)
+	}
+}
+
+func (c *checker) exprs(exprs []ast.Expr) {
+	for _, expr := range exprs {
+		c.expr(expr)
+	}
+}
+
+func (c *checker) expr(expr ast.Expr) {
+	switch e := expr.(type) {
+	case *ast.AttrGetExpr:
+		if path, ok := spcPath(e); ok {
+			c.check(e.Line(), path)
+			return
+		}
+		c.expr(e.Object)
+		c.expr(e.Key)
+	case *ast.FuncCallExpr:
+		if e.Receiver != nil {
+			if path, ok := spcPath(e.Receiver); ok {
+				c.check(e.Line(), append(path, e.Method))
+			} else {
+				c.expr(e.Receiver)
+			}
+		}
+		c.expr(e.Func)
+		c.exprs(e.Args)
+	case *ast.TableExpr:
+		for _, field := range e.Fields {
+			c.expr(field.Key)
+			c.expr(field.Value)
+		}
+	case *ast.LogicalOpExpr:
+		c.expr(e.Lhs)
+		c.expr(e.Rhs)
+	case *ast.RelationalOpExpr:
+		c.expr(e.Lhs)
+		c.expr(e.Rhs)
+	case *ast.StringConcatOpExpr:
+		c.expr(e.Lhs)
+		c.expr(e.Rhs)
+	case *ast.ArithmeticOpExpr:
+		c.expr(e.Lhs)
+		c.expr(e.Rhs)
+	case *ast.UnaryMinusOpExpr:
+		c.expr(e.Expr)
+	case *ast.UnaryNotOpExpr:
+		c.expr(e.Expr)
+	case *ast.UnaryLenOpExpr:
+		c.expr(e.Expr)
+	case *ast.FunctionExpr:
+		if e.ParList != nil {
+			c.declare(e.ParList.Names...)
+		}
+		c.stmts(e.Stmts)
+	}
+}
+
+// check проверяет путь вида spc.module.member
+func (c *checker) check(line int, path []string) {
+	if c.shadowed || len(path) == 0 {
+		return
+	}
+
+	if path[0] == "params" {
+		if len(path) > 1 && c.params != nil && !slices.Contains(c.params, path[1]) {
+			c.warn(line, "spc.params.%s: parameter is not declared", path[1])
+		}
+		return
+	}
+
+	module, ok := c.schema.Modules[path[0]]
+	if !ok {
+		c.warn(line, "spc.%s: unknown module", path[0])
+		return
+	}
+	if module.Capability != "" && !slices.Contains(c.capabilities, module.Capability) &&
+		!c.missing[module.Capability] {
+		c.missing[module.Capability] = true
+		c.warn(line, "spc.%s: module requires capability %q", path[0], module.Capability)
+	}
+	if len(path) < 2 {
+		return
+	}
+	if _, ok := module.Functions[path[1]]; ok {
+		return
+	}
+	if _, ok := module.Fields[path[1]]; ok {
+		return
+	}
+	c.warn(line, "spc.%s.%s: unknown member of module %s", path[0], path[1], path[0])
+}
+
+// spcPath возвращает ключи цепочки spc.a.b..., если выражение — такая цепочка
+// со строковыми ключами
+func spcPath(expr ast.Expr) ([]string, bool) {
+	switch e := expr.(type) {
+	case *ast.IdentExpr:
+		return []string{}, e.Value == "spc"
+	case *ast.AttrGetExpr:
+		key, ok := e.Key.(*ast.StringExpr)
+		if !ok {
+			return nil, false
+		}
+		path, ok := spcPath(e.Object)
+		if !ok {
+			return nil, false
+		}
+		return append(path, key.Value), true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,98 @@
+package luaApi
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+type ctxKey string
+
+const commandIDKey ctxKey = "commandID"
+
+// WithCommandID добавляет ID выполняемой команды в контекст, который
+// затем передаётся в lua-состояние через LState.SetContext
+func WithCommandID(ctx context.Context, commandID string) context.Context {
+	return context.WithValue(ctx, commandIDKey, commandID)
+}
+
+// CommandID возвращает ID команды, скрипт которой выполняется в l
+func CommandID(l *lua.LState) (string, bool) {
+	ctx := l.Context()
+	if ctx == nil {
+		return "", false
+	}
+	commandID, ok := ctx.Value(commandIDKey).(string)
+	return commandID, ok && commandID != ""
+}
+
+// максимальное число сообщений, которое сохраняет Logs
+const maxLogEntries = 1000
+
+const logsKey ctxKey = "logs"
+
+// LogEntry — сообщение, записанное скриптом через spc.log
+type LogEntry struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+}
+
+// Logs собирает сообщения spc.log одного выполнения
+type Logs struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	dropped int
+	onEntry func(LogEntry)
+}
+
+// NewStreamingLogs создаёт сборщик, который дополнительно передаёт каждое
+// сообщение в onEntry сразу после записи
+func NewStreamingLogs(onEntry func(LogEntry)) *Logs {
+	return &Logs{onEntry: onEntry}
+}
+
+// WithLogs добавляет в контекст сборщик сообщений spc.log
+func WithLogs(ctx context.Context, logs *Logs) context.Context {
+	return context.WithValue(ctx, logsKey, logs)
+}
+
+// CaptureLog сохраняет сообщение скрипта, если в контексте l есть сборщик
+func CaptureLog(l *lua.LState, level string, message string) {
+	ctx := l.Context()
+	if ctx == nil {
+		return
+	}
+	logs, ok := ctx.Value(logsKey).(*Logs)
+	if !ok {
+		return
+	}
+
+	entry := LogEntry{
+		Time:    time.Now(),
+		Level:   level,
+		Message: message,
+	}
+
+	logs.mu.Lock()
+	if len(logs.entries) < maxLogEntries {
+		logs.entries = append(logs.entries, entry)
+	} else {
+		logs.dropped++
+	}
+	logs.mu.Unlock()
+
+	if logs.onEntry != nil {
+		logs.onEntry(entry)
+	}
+}
+
+// Entries возвращает собранные сообщения и число отброшенных сверх лимита
+func (lg *Logs) Entries() ([]LogEntry, int) {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	return slices.Clone(lg.entries), lg.dropped
+}
@@ -0,0 +1,157 @@
+package luaApi
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"runtime/metrics"
+	"strings"
+	"sync/atomic"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// как часто (в инструкциях) проверяется потребление памяти
+const memoryCheckInterval = 10_000
+
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+var (
+	ErrInstructionLimit = errors.New("instruction limit exceeded")
+	ErrMemoryLimit      = errors.New("memory limit exceeded")
+	ErrStackLimit       = errors.New("stack limit exceeded")
+)
+
+// Limits ограничивает ресурсы lua-состояния, нулевые значения — без ограничения
+type Limits struct {
+	CallStackSize   int
+	RegistrySize    int
+	RegistryMaxSize int
+	// Instructions — бюджет инструкций виртуальной машины
+	Instructions int64
+	// Memory — допустимый прирост кучи процесса в байтах, оценка грубая:
+	// куча общая для всего агента
+	Memory int64
+}
+
+// limitedContext считает инструкции: виртуальная машина gopher-lua
+// вызывает Done перед каждой из них
+type limitedContext struct {
+	context.Context
+	cancel context.CancelCauseFunc
+	limits Limits
+	steps  atomic.Int64
+	sample []metrics.Sample
+	heap   int64
+}
+
+// WithLimits возвращает контекст для LState.SetContext, который отменяется
+// с причиной ErrInstructionLimit или ErrMemoryLimit при превышении лимитов
+func WithLimits(ctx context.Context, limits Limits) (context.Context, context.CancelFunc) {
+	inner, cancel := context.WithCancelCause(ctx)
+
+	c := &limitedContext{
+		Context: inner,
+		cancel:  cancel,
+		limits:  limits,
+		sample:  []metrics.Sample{{Name: heapMetric}},
+	}
+	c.heap = c.heapBytes()
+
+	return c, func() { cancel(context.Canceled) }
+}
+
+func (c *limitedContext) Done() <-chan struct{} {
+	steps := c.steps.Add(1)
+
+	if c.limits.Instructions > 0 && steps > c.limits.Instructions {
+		c.cancel(ErrInstructionLimit)
+	} else if c.limits.Memory > 0 && steps%memoryCheckInterval == 0 &&
+		c.heapBytes()-c.heap > c.limits.Memory {
+		c.cancel(ErrMemoryLimit)
+	}
+
+	return c.Context.Done()
+}
+
+func (c *limitedContext) heapBytes() int64 {
+	metrics.Read(c.sample)
+	if c.sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return int64(c.sample[0].Value.Uint64())
+}
+
+// LimitError возвращает ошибку превышения лимита, если выполнение в l
+// завершилось из-за него, иначе nil
+func LimitError(l *lua.LState, err error) error {
+	if cause := context.Cause(l.Context()); errors.Is(cause, ErrInstructionLimit) ||
+		errors.Is(cause, ErrMemoryLimit) {
+		return cause
+	}
+
+	if isOverflow(err) {
+		return ErrStackLimit
+	}
+
+	return nil
+}
+
+// переполнения стеков gopher-lua сообщает обычной ошибкой выполнения через
+// RaiseError, который добавляет к сообщению только позицию в скрипте
+var overflowMessage = regexp.MustCompile(`^(?:.*:\d+:)? ?(?:stack|registry) overflow$`)
+
+// первая строка трассировки ошибки, которую скрипт бросил сам через error()
+const scriptErrorFrame = "[G]: in function 'error'"
+
+// isOverflow отличает переполнение стека от ошибки скрипта с тем же текстом
+func isOverflow(err error) bool {
+	apiErr, ok := errors.AsType[*lua.ApiError](err)
+	if !ok || apiErr.Type != lua.ApiErrorRun {
+		return false
+	}
+
+	message, ok := apiErr.Object.(lua.LString)
+	if !ok || !overflowMessage.MatchString(string(message)) {
+		return false
+	}
+
+	_, trace, _ := strings.Cut(apiErr.StackTrace, "\n")
+	firstFrame, _, _ := strings.Cut(trace, "\n")
+	return strings.TrimSpace(firstFrame) != scriptErrorFrame
+}
+
+// raiseMemoryLimit прерывает выполнение из-за нехватки памяти
+func raiseMemoryLimit(l *lua.LState, name string) {
+	if c, ok := l.Context().(*limitedContext); ok {
+		c.cancel(ErrMemoryLimit)
+	}
+	l.RaiseError("%s: %s", name, ErrMemoryLimit.Error())
+}
+
+// guardStringRep не даёт string.rep одним вызовом выделить больше лимита памяти
+func guardStringRep(l *lua.LState, memory int64) {
+	str, ok := l.GetGlobal(lua.StringLibName).(*lua.LTable)
+	if !ok {
+		return
+	}
+	rep, ok := l.GetField(str, "rep").(*lua.LFunction)
+	if !ok {
+		return
+	}
+
+	l.SetField(str, "rep", l.NewFunction(func(l *lua.LState) int {
+		s := l.CheckString(1)
+		n := l.CheckInt64(2)
+		if n > 0 && int64(len(s)) > memory/n {
+			raiseMemoryLimit(l, "string.rep")
+			return 0
+		}
+
+		l.Push(rep)
+		l.Push(lua.LString(s))
+		l.Push(lua.LNumber(n))
+		l.Call(2, 1)
+		return 1
+	}))
+}
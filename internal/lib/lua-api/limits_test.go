@@ -0,0 +1,54 @@
+package luaApi
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLimitErrorStackOverflow(t *testing.T) {
+	limits := Limits{CallStackSize: 64, RegistrySize: 256, RegistryMaxSize: 1024}
+
+	tests := []struct {
+		name   string
+		script string
+		want   error
+	}{
+		{"call stack", `local function f() return 1 + f() end f()`, ErrStackLimit},
+		{
+			"registry",
+			`local t = {} for i = 1, 3000 do t[i] = i end return unpack(t)`,
+			ErrStackLimit,
+		},
+		{"error with the phrase", `error("stack overflow while parsing")`, nil},
+		{"error with the exact message", `error("stack overflow")`, nil},
+		{"error without position", `error("registry overflow", 0)`, nil},
+		{
+			"caught and rethrown",
+			`local function f() return 1 + f() end
+			local ok, err = pcall(f)
+			error(err, 0)`,
+			nil,
+		},
+		{"other error", `error("boom")`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := WithLimits(context.Background(), limits)
+			defer cancel()
+
+			l := NewState(limits)
+			defer l.Close()
+			l.SetContext(ctx)
+
+			err := l.DoString(tt.script)
+			if err == nil {
+				t.Fatal("expected the script to fail")
+			}
+
+			if got := LimitError(l, err); got != tt.want {
+				t.Errorf("LimitError(%q) = %v, want %v", err, got, tt.want)
+			}
+		})
+	}
+}
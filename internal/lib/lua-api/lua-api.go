@@ -1,6 +1,8 @@
 package luaApi
 
 import (
+	"slices"
+
 	lua "github.com/yuin/gopher-lua"
 )
 
@@ -13,34 +15,65 @@ type Module interface {
 }
 
 type APISchema struct {
-	Version string               `json:"version"`
-	Modules map[string]ModuleDoc `json:"modules"`
+	Version    string               `json:"version"`
+	Modules    map[string]ModuleDoc `json:"modules"`
+	Libraries  []LibraryDoc         `json:"libraries,omitempty"`
+	ParamTypes []ParamTypeDoc       `json:"paramTypes,omitempty"`
+}
+
+type registeredModule struct {
+	module     Module
+	capability bool
 }
 
 type Registry struct {
 	version string
-	modules map[string]Module
+	modules map[string]registeredModule
 }
 
 func NewRegistry(version string) *Registry {
 	return &Registry{
 		version: version,
-		modules: make(map[string]Module),
+		modules: make(map[string]registeredModule),
 	}
 }
 
-// Register добавляет модуль под именем name (это имя поля в spc.*)
+// Register добавляет модуль под именем name (это имя поля в spc.*),
+// модуль доступен всем скриптам
 func (r *Registry) Register(name string, m Module) *Registry {
-	r.modules[name] = m
+	r.modules[name] = registeredModule{module: m}
 	return r
 }
 
-// BuildTable собирает lua-таблицу spc для выполнения скрипта
-func (r *Registry) BuildTable(l *lua.LState) *lua.LTable {
+// RegisterCapability добавляет модуль, который доступен только командам,
+// получившим одноимённую возможность (capability)
+func (r *Registry) RegisterCapability(name string, m Module) *Registry {
+	r.modules[name] = registeredModule{module: m, capability: true}
+	return r
+}
+
+// Capabilities возвращает отсортированные имена модулей, требующих разрешения
+func (r *Registry) Capabilities() []string {
+	capabilities := make([]string, 0, len(r.modules))
+	for name, registered := range r.modules {
+		if registered.capability {
+			capabilities = append(capabilities, name)
+		}
+	}
+	slices.Sort(capabilities)
+	return capabilities
+}
+
+// BuildTable собирает lua-таблицу spc для выполнения скрипта, в неё
+// попадают общие модули и модули из capabilities
+func (r *Registry) BuildTable(l *lua.LState, capabilities []string) *lua.LTable {
 	spc := l.NewTable()
-	for name, module := range r.modules {
+	for name, registered := range r.modules {
+		if registered.capability && !slices.Contains(capabilities, name) {
+			continue
+		}
 		t := l.NewTable()
-		module.Register(l, t)
+		registered.module.Register(l, t)
 		l.SetField(spc, name, t)
 	}
 	return spc
@@ -52,8 +85,12 @@ func (r *Registry) Schema() APISchema {
 		Version: r.version,
 		Modules: make(map[string]ModuleDoc, len(r.modules)),
 	}
-	for name, module := range r.modules {
-		schema.Modules[name] = module.Doc()
+	for name, registered := range r.modules {
+		doc := registered.module.Doc()
+		if registered.capability {
+			doc.Capability = name
+		}
+		schema.Modules[name] = doc
 	}
 	return schema
 }
@@ -0,0 +1,174 @@
+package luaApi
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// зарезервированные слова Lua нельзя использовать как имена параметров
+var luaKeywords = []string{
+	"and", "break", "do", "else", "elseif", "end", "false", "for", "function", "goto",
+	"if", "in", "local", "nil", "not", "or", "repeat", "return", "then", "true", "until", "while",
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// LuaLS формирует файл определений ---@meta для Lua Language Server.
+// params описывает spc.params конкретной команды; если он nil,
+// spc.params описывается как произвольная таблица.
+func LuaLS(schema APISchema, params []ParamDoc) string {
+	var b strings.Builder
+
+	b.WriteString("---@meta\n")
+	fmt.Fprintf(&b, "-- spc API %s, generated by smart-pc-agent, do not edit\n\n", schema.Version)
+
+	b.WriteString("---@class spc\n")
+	b.WriteString("spc = {}\n")
+
+	for _, name := range slices.Sorted(maps.Keys(schema.Modules)) {
+		writeModule(&b, name, schema.Modules[name])
+	}
+
+	b.WriteString("\n")
+	writeComment(&b, "parameters passed to the command")
+	b.WriteString("---@class spc.params\n")
+	if params == nil {
+		b.WriteString("---@field [string] string|number|boolean|nil\n")
+	}
+	for _, param := range params {
+		optional := ""
+		if param.Optional {
+			optional = "?"
+		}
+		fmt.Fprintf(&b, "---@field %s%s %s", fieldName(param.Name), optional, fieldType(param))
+		if param.Description != "" {
+			b.WriteString(" " + oneLine(param.Description))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("spc.params = {}\n")
+
+	return b.String()
+}
+
+func writeModule(b *strings.Builder, name string, module ModuleDoc) {
+	b.WriteString("\n")
+
+	description := module.Description
+	if module.Capability != "" {
+		description += fmt.Sprintf("\n\nRequires capability `%s`.", module.Capability)
+	}
+	writeComment(b, description)
+
+	fmt.Fprintf(b, "---@class spc.%s\n", name)
+	for _, field := range slices.Sorted(maps.Keys(module.Fields)) {
+		doc := module.Fields[field]
+		fmt.Fprintf(b, "---@field %s %s %s\n", field, luaType(doc.Type), oneLine(doc.Description))
+	}
+	fmt.Fprintf(b, "spc.%s = {}\n", name)
+
+	for _, function := range slices.Sorted(maps.Keys(module.Functions)) {
+		writeFunction(b, name, function, module.Functions[function])
+	}
+}
+
+func writeFunction(b *strings.Builder, module string, name string, function FunctionDoc) {
+	b.WriteString("\n")
+
+	description := function.Description
+	if function.Example != "" {
+		description += "\n\n```lua\n" + function.Example + "\n```"
+	}
+	writeComment(b, description)
+
+	names := make([]string, len(function.Params))
+	for i, param := range function.Params {
+		names[i] = paramName(param.Name)
+		optional := ""
+		if param.Optional {
+			optional = "?"
+		}
+		fmt.Fprintf(
+			b,
+			"---@param %s%s %s %s\n",
+			names[i],
+			optional,
+			luaType(param.Type),
+			oneLine(param.Description),
+		)
+	}
+	for _, ret := range function.Returns {
+		fmt.Fprintf(b, "---@return %s # %s\n", luaType(ret.Type), oneLine(ret.Description))
+	}
+
+	fmt.Fprintf(b, "function spc.%s.%s(%s) end\n", module, name, strings.Join(names, ", "))
+}
+
+func writeComment(b *strings.Builder, text string) {
+	if text == "" {
+		return
+	}
+	for line := range strings.SplitSeq(text, "\n") {
+		b.WriteString("---" + line + "\n")
+	}
+}
+
+// fieldType описывает допустимые значения объединением литералов,
+// например "low"|"high" или ("a"|"b")[] для списка
+func fieldType(param ParamDoc) string {
+	t := luaType(param.Type)
+	if len(param.Enum) == 0 {
+		return t
+	}
+
+	element, isList := strings.CutSuffix(t, "[]")
+	literals := make([]string, len(param.Enum))
+	for i, value := range param.Enum {
+		if element == TypeString {
+			value = strconv.Quote(value)
+		}
+		literals[i] = value
+	}
+
+	union := strings.Join(literals, "|")
+	if isList {
+		return "(" + union + ")[]"
+	}
+	return union
+}
+
+func luaType(t string) string {
+	if element, isList := strings.CutSuffix(t, "[]"); isList {
+		return luaType(element) + "[]"
+	}
+	switch t {
+	case TypeString, TypeNumber, TypeInteger, TypeBoolean, TypeTable, TypeAny:
+		return t
+	default:
+		return TypeAny
+	}
+}
+
+func paramName(name string) string {
+	if slices.Contains(luaKeywords, name) {
+		return name + "_"
+	}
+	return name
+}
+
+// fieldName записывает имя, которое не является идентификатором Lua
+// (my-param, end, a b), в скобках: ---@field ["my-param"] string
+func fieldName(name string) string {
+	if identifierPattern.MatchString(name) && !slices.Contains(luaKeywords, name) {
+		return name
+	}
+	return "[" + strconv.Quote(name) + "]"
+}
+
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
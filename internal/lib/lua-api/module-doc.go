@@ -3,6 +3,7 @@ package luaApi
 const (
 	TypeString  = "string"
 	TypeNumber  = "number"
+	TypeInteger = "integer"
 	TypeBoolean = "boolean"
 	TypeTable   = "table"
 	TypeAny     = "any"
@@ -14,6 +15,8 @@ type ParamDoc struct {
 	Type        string `json:"type"`
 	Description string `json:"description"`
 	Optional    bool   `json:"optional,omitempty"`
+	// Enum — допустимые значения, если они ограничены
+	Enum []string `json:"enum,omitempty"`
 }
 
 // ReturnDoc описывает возвращаемое значение
@@ -30,8 +33,36 @@ type FunctionDoc struct {
 	Example     string      `json:"example,omitempty"`
 }
 
+// FieldDoc описывает поле модуля, которое не является функцией
+type FieldDoc struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
 // ModuleDoc описывает весь модуль
 type ModuleDoc struct {
 	Description string                 `json:"description"`
 	Functions   map[string]FunctionDoc `json:"functions"`
+	Fields      map[string]FieldDoc    `json:"fields,omitempty"`
+	// Capability — имя возможности, которую нужно выдать команде для доступа к модулю
+	Capability string `json:"capability,omitempty"`
+}
+
+// LibraryDoc описывает пользовательскую библиотеку, доступную через require
+type LibraryDoc struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Version     int64  `json:"version"`
+}
+
+// ParamTypeDoc описывает тип параметра команды, чтобы клиент мог выбрать поле ввода
+type ParamTypeDoc struct {
+	ID          int16  `json:"id"`
+	Name        string `json:"name"`
+	LuaType     string `json:"luaType"`
+	Description string `json:"description"`
+	// Constraints — поля параметра, которые учитываются для этого типа
+	Constraints []string `json:"constraints,omitempty"`
+	// ItemTypes — допустимые типы элементов списка
+	ItemTypes []int16 `json:"itemTypes,omitempty"`
 }
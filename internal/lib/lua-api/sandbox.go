@@ -0,0 +1,59 @@
+package luaApi
+
+import (
+	lua "github.com/yuin/gopher-lua"
+)
+
+// стандартные библиотеки, доступные скриптам; io, debug и channel не
+// открываются, доступ к файлам и процессам идёт только через модули spc
+var openLibs = []struct {
+	name string
+	fn   lua.LGFunction
+}{
+	{lua.LoadLibName, lua.OpenPackage},
+	{lua.BaseLibName, lua.OpenBase},
+	{lua.TabLibName, lua.OpenTable},
+	{lua.StringLibName, lua.OpenString},
+	{lua.MathLibName, lua.OpenMath},
+	{lua.CoroutineLibName, lua.OpenCoroutine},
+	{lua.OsLibName, lua.OpenOs},
+}
+
+var (
+	removedGlobals  = []string{"dofile", "loadfile"}
+	removedOsFields = []string{
+		"execute", "exit", "getenv", "remove", "rename", "setenv", "setlocale", "tmpname",
+	}
+)
+
+// NewState создаёт lua-состояние с лимитами limits и только с безопасной
+// частью стандартной библиотеки: из os остаются time, clock, date и difftime
+func NewState(limits Limits) *lua.LState {
+	l := lua.NewState(lua.Options{
+		SkipOpenLibs:    true,
+		CallStackSize:   limits.CallStackSize,
+		RegistrySize:    limits.RegistrySize,
+		RegistryMaxSize: limits.RegistryMaxSize,
+	})
+
+	for _, lib := range openLibs {
+		l.Push(l.NewFunction(lib.fn))
+		l.Push(lua.LString(lib.name))
+		l.Call(1, 0)
+	}
+
+	for _, name := range removedGlobals {
+		l.SetGlobal(name, lua.LNil)
+	}
+	if os, ok := l.GetGlobal(lua.OsLibName).(*lua.LTable); ok {
+		for _, name := range removedOsFields {
+			l.SetField(os, name, lua.LNil)
+		}
+	}
+
+	if limits.Memory > 0 {
+		guardStringRep(l, limits.Memory)
+	}
+
+	return l
+}
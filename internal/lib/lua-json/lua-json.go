@@ -0,0 +1,227 @@
+// Package luaJson converts Lua values to JSON-compatible Go values and back.
+//
+// Tables whose keys are exactly 1..n are treated as arrays, any other table
+// is an object with string keys. An empty table is encoded as an empty object
+// unless it is marked as an array with MarkArray. JSON null is represented by
+// the per-state sentinel returned from Null.
+package luaJson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	nullRegistryKey = "luaJson.null"
+	typeField       = "__jsontype"
+	typeArray       = "array"
+)
+
+type nullValue struct{}
+
+var (
+	ErrUnsupportedType = errors.New("unsupported type")
+	ErrCycle           = errors.New("table contains a cycle")
+	ErrInvalidNumber   = errors.New("number is NaN or infinite")
+	ErrInvalidKey      = errors.New("object key must be a string or a number")
+)
+
+// Marshal encodes a Lua value to JSON
+func Marshal(value lua.LValue) ([]byte, error) {
+	goValue, err := ToGo(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(goValue)
+}
+
+// Unmarshal decodes JSON into a Lua value
+func Unmarshal(l *lua.LState, data []byte) (lua.LValue, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after top-level value")
+	}
+
+	return FromGo(l, value), nil
+}
+
+// Null returns the value that stands for JSON null in the given state
+func Null(l *lua.LState) lua.LValue {
+	if null, ok := l.G.Registry.RawGetString(nullRegistryKey).(*lua.LUserData); ok {
+		return null
+	}
+
+	null := l.NewUserData()
+	null.Value = nullValue{}
+	l.G.Registry.RawSetString(nullRegistryKey, null)
+	return null
+}
+
+// MarkArray makes the table always encode as an array, even when it is empty
+func MarkArray(l *lua.LState, table *lua.LTable) {
+	metatable, ok := table.Metatable.(*lua.LTable)
+	if !ok {
+		metatable = l.NewTable()
+		l.SetMetatable(table, metatable)
+	}
+	metatable.RawSetString(typeField, lua.LString(typeArray))
+}
+
+// FromGo converts a value produced by encoding/json to a Lua value
+func FromGo(l *lua.LState, value any) lua.LValue {
+	switch v := value.(type) {
+	case nil:
+		return Null(l)
+	case bool:
+		return lua.LBool(v)
+	case float64:
+		return lua.LNumber(v)
+	case json.Number:
+		number, err := v.Float64()
+		if err != nil {
+			return lua.LString(v.String())
+		}
+		return lua.LNumber(number)
+	case string:
+		return lua.LString(v)
+	case []any:
+		table := l.CreateTable(len(v), 0)
+		for _, item := range v {
+			table.Append(FromGo(l, item))
+		}
+		MarkArray(l, table)
+		return table
+	case map[string]any:
+		table := l.CreateTable(0, len(v))
+		for key, item := range v {
+			table.RawSetString(key, FromGo(l, item))
+		}
+		return table
+	default:
+		return lua.LString(fmt.Sprint(v))
+	}
+}
+
+// ToGo converts a Lua value to nil, bool, float64, string, []any or map[string]any
+func ToGo(value lua.LValue) (any, error) {
+	return toGo(value, make(map[*lua.LTable]struct{}))
+}
+
+func toGo(value lua.LValue, visited map[*lua.LTable]struct{}) (any, error) {
+	switch v := value.(type) {
+	case *lua.LNilType:
+		return nil, nil
+	case lua.LBool:
+		return bool(v), nil
+	case lua.LNumber:
+		f := float64(v)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, ErrInvalidNumber
+		}
+		return f, nil
+	case lua.LString:
+		return string(v), nil
+	case *lua.LTable:
+		if _, ok := visited[v]; ok {
+			return nil, ErrCycle
+		}
+		visited[v] = struct{}{}
+		defer delete(visited, v)
+
+		if isMarkedArray(v) || isArray(v) {
+			return arrayToGo(v, visited)
+		}
+		return objectToGo(v, visited)
+	case *lua.LUserData:
+		if _, ok := v.Value.(nullValue); ok {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, value.Type().String())
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, value.Type().String())
+	}
+}
+
+func isMarkedArray(table *lua.LTable) bool {
+	metatable, ok := table.Metatable.(*lua.LTable)
+	return ok && metatable.RawGetString(typeField) == lua.LString(typeArray)
+}
+
+func isArray(table *lua.LTable) bool {
+	length := table.Len()
+	if length == 0 {
+		return false
+	}
+
+	count := 0
+	array := true
+	table.ForEach(func(key lua.LValue, _ lua.LValue) {
+		count++
+		number, ok := key.(lua.LNumber)
+		if !ok || float64(number) != math.Trunc(float64(number)) ||
+			number < 1 || int(number) > length {
+			array = false
+		}
+	})
+
+	return array && count == length
+}
+
+func arrayToGo(table *lua.LTable, visited map[*lua.LTable]struct{}) (any, error) {
+	length := table.Len()
+	result := make([]any, length)
+	for i := 1; i <= length; i++ {
+		item, err := toGo(table.RawGetInt(i), visited)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+		result[i-1] = item
+	}
+	return result, nil
+}
+
+func objectToGo(table *lua.LTable, visited map[*lua.LTable]struct{}) (any, error) {
+	result := make(map[string]any)
+
+	var err error
+	table.ForEach(func(key lua.LValue, value lua.LValue) {
+		if err != nil {
+			return
+		}
+
+		var name string
+		switch k := key.(type) {
+		case lua.LString:
+			name = string(k)
+		case lua.LNumber:
+			name = strconv.FormatFloat(float64(k), 'f', -1, 64)
+		default:
+			err = fmt.Errorf("%w: got %s", ErrInvalidKey, key.Type().String())
+			return
+		}
+
+		item, itemErr := toGo(value, visited)
+		if itemErr != nil {
+			err = fmt.Errorf("%s: %w", name, itemErr)
+			return
+		}
+		result[name] = item
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,77 @@
+package executions
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/domain/models/message"
+)
+
+var ErrCancelled = errors.New("execution cancelled")
+
+type execution struct {
+	cancel context.CancelCauseFunc
+}
+
+// Registry хранит выполняющиеся команды по ID сообщения, которое их запустило
+type Registry struct {
+	mu      sync.Mutex
+	running map[string]*execution
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		running: make(map[string]*execution),
+	}
+}
+
+// Start регистрирует выполнение под id и возвращает контекст, который
+// отменяется через Cancel. Возвращаемую функцию нужно вызвать по завершении.
+func (r *Registry) Start(ctx context.Context, id string) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	if id == "" {
+		return ctx, func() { cancel(nil) }
+	}
+
+	exec := &execution{cancel: cancel}
+
+	r.mu.Lock()
+	r.running[id] = exec
+	r.mu.Unlock()
+
+	return ctx, func() {
+		r.mu.Lock()
+		if r.running[id] == exec {
+			delete(r.running, id)
+		}
+		r.mu.Unlock()
+
+		cancel(nil)
+	}
+}
+
+// Cancel отменяет выполнение с указанным id, false если оно не найдено
+func (r *Registry) Cancel(id string) bool {
+	r.mu.Lock()
+	exec, ok := r.running[id]
+	if ok {
+		delete(r.running, id)
+	}
+	r.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	exec.cancel(ErrCancelled)
+	return true
+}
+
+// MessageID возвращает ID сообщения (MQTT correlation data)
+func MessageID(msg *message.Message) string {
+	if msg.Publish == nil || msg.Publish.Properties == nil {
+		return ""
+	}
+	return string(msg.Publish.Properties.CorrelationData)
+}
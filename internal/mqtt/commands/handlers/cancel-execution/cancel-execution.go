@@ -0,0 +1,50 @@
+package cancelExecution
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/commands"
+	"github.com/MaxRomanov007/smart-pc-go-lib/domain/models/message"
+	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
+)
+
+type Parameter struct {
+	MessageID string `json:"messageId"`
+}
+
+type ExecutionCanceller interface {
+	Cancel(id string) bool
+}
+
+func New(log *slog.Logger, canceller ExecutionCanceller) commands.CommandFunc {
+	return func(ctx context.Context, msg *message.Message) error {
+		const op = "commands.handlers.cancel-execution"
+
+		log := log.With(sl.Op(op), sl.MsgID(msg.Publish))
+
+		parameter, err := message.Parameter[Parameter](msg)
+		if err != nil {
+			log.Warn(
+				"failed to parse message parameter",
+				slog.Any("parameter", msg.Data.Parameter),
+				sl.Err(err),
+			)
+			return commands.Error("failed to get message id")
+		}
+
+		if parameter.MessageID == "" {
+			log.Warn("empty message id")
+			return commands.Error("message id is required")
+		}
+
+		if !canceller.Cancel(parameter.MessageID) {
+			log.Info("execution not found", slog.String("messageId", parameter.MessageID))
+			return commands.Error("execution not found")
+		}
+
+		log.Info("execution cancelled", slog.String("messageId", parameter.MessageID))
+
+		return nil
+	}
+}
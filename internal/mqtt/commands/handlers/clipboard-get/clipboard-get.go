@@ -0,0 +1,48 @@
+package clipboardGet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"smart-pc-agent/internal/lib/cross-platform/clipboard"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/commands"
+	"github.com/MaxRomanov007/smart-pc-go-lib/domain/models/message"
+	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
+)
+
+type Result struct {
+	Text string `json:"text"`
+}
+
+type ResultPublisher interface {
+	PublishResult(ctx context.Context, msg *message.Message, result json.RawMessage) error
+}
+
+func New(log *slog.Logger, resultPublisher ResultPublisher) commands.CommandFunc {
+	return func(ctx context.Context, msg *message.Message) error {
+		const op = "commands.handlers.clipboard-get"
+
+		log := log.With(sl.Op(op), sl.MsgID(msg.Publish))
+
+		text, err := clipboard.Read()
+		if err != nil {
+			log.Warn("failed to read clipboard", sl.Err(err))
+			return commands.Error("failed to get clipboard")
+		}
+
+		data, err := json.Marshal(Result{Text: text})
+		if err != nil {
+			return fmt.Errorf("%s: failed to marshal result: %w", op, err)
+		}
+
+		if err := resultPublisher.PublishResult(ctx, msg, data); err != nil {
+			return fmt.Errorf("%s: failed to publish result: %w", op, err)
+		}
+
+		log.Info("clipboard sent", slog.Int("length", len(text)))
+
+		return nil
+	}
+}
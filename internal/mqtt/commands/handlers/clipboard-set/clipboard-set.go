@@ -0,0 +1,42 @@
+package clipboardSet
+
+import (
+	"context"
+	"log/slog"
+	"smart-pc-agent/internal/lib/cross-platform/clipboard"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/commands"
+	"github.com/MaxRomanov007/smart-pc-go-lib/domain/models/message"
+	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
+)
+
+type Parameter struct {
+	Text string `json:"text"`
+}
+
+func New(log *slog.Logger) commands.CommandFunc {
+	return func(ctx context.Context, msg *message.Message) error {
+		const op = "commands.handlers.clipboard-set"
+
+		log := log.With(sl.Op(op), sl.MsgID(msg.Publish))
+
+		parameter, err := message.Parameter[Parameter](msg)
+		if err != nil {
+			log.Warn(
+				"failed to parse message parameter",
+				slog.Any("parameter", msg.Data.Parameter),
+				sl.Err(err),
+			)
+			return commands.Error("failed to get clipboard text")
+		}
+
+		if err := clipboard.Write(parameter.Text); err != nil {
+			log.Warn("failed to write clipboard", sl.Err(err))
+			return commands.Error("failed to set clipboard")
+		}
+
+		log.Info("clipboard set", slog.Int("length", len(parameter.Text)))
+
+		return nil
+	}
+}
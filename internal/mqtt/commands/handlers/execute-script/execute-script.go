@@ -3,24 +3,19 @@ package executeScript
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"smart-pc-agent/internal/domain/models"
-	luaApi "smart-pc-agent/internal/lib/lua-api"
+	"smart-pc-agent/internal/mqtt/commands/executions"
+	"smart-pc-agent/internal/mqtt/commands/runner"
 	"smart-pc-agent/internal/storage"
-	"strconv"
+	"time"
 
 	"github.com/MaxRomanov007/smart-pc-go-lib/commands"
 	"github.com/MaxRomanov007/smart-pc-go-lib/domain/models/message"
 	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
-	lua "github.com/yuin/gopher-lua"
-)
-
-const (
-	TypeBool   = 1
-	TypeNumber = 2
-	TypeString = 3
 )
 
 type CommandGetter interface {
@@ -31,11 +26,17 @@ type CommandParamsGetter interface {
 	GetCommandParams(ctx context.Context, commandId string) ([]models.CommandParameter, error)
 }
 
+type ResultPublisher interface {
+	PublishResult(ctx context.Context, msg *message.Message, result json.RawMessage) error
+}
+
 func New(
 	log *slog.Logger,
 	commandGetter CommandGetter,
 	paramsGetter CommandParamsGetter,
-	registry *luaApi.Registry,
+	scriptRunner *runner.Runner,
+	running *executions.Registry,
+	resultPublisher ResultPublisher,
 ) commands.CommandFunc {
 	return func(ctx context.Context, msg *message.Message) error {
 		const op = "commands.handlers.execute-script"
@@ -66,91 +67,31 @@ func New(
 			return commands.Error("failed to get message parameters")
 		}
 
-		l := lua.NewState()
-		defer l.Close()
-
-		spc := registry.BuildTable(l)
-		l.SetField(spc, "params", createParamsTable(log, l, scriptParams, messageParams))
-		l.SetGlobal("spc", spc)
-
-		err = l.DoString(command.Script)
-		if apiErr, ok := errors.AsType[*lua.ApiError](err); ok {
-			switch apiErr.Type {
-			case lua.ApiErrorSyntax:
-				return commands.Error("syntax error: " + apiErr.Error())
-			case lua.ApiErrorFile:
-				return commands.Error("file error: " + apiErr.Error())
-			case lua.ApiErrorRun:
-				return commands.Error("run error: " + apiErr.Error())
-			case lua.ApiErrorError:
-				return commands.Error("error: " + apiErr.Error())
-			case lua.ApiErrorPanic:
-				return commands.Error("panic error: " + apiErr.Error())
-			default:
-				return commands.Error("api error: " + apiErr.Error())
-			}
+		ctx, done := running.Start(ctx, executions.MessageID(msg))
+		defer done()
+
+		res, err := scriptRunner.Run(ctx, runner.Script{
+			CommandID:    command.ID,
+			Source:       command.Script,
+			Capabilities: command.Capabilities,
+			Parameters:   scriptParams,
+			Values:       messageParams,
+			Timeout:      time.Duration(command.TimeoutMs) * time.Millisecond,
+		})
+		if scriptErr, ok := errors.AsType[*runner.Error](err); ok {
+			return commands.Error(scriptErr.Error())
 		}
 		if err != nil {
-			return fmt.Errorf("%s: failed to execute script: %w", op, err)
+			return fmt.Errorf("%s: failed to run script: %w", op, err)
 		}
-
-		return nil
-	}
-}
-
-func createParamsTable(
-	logger *slog.Logger,
-	l *lua.LState,
-	scriptParams []models.CommandParameter,
-	messageParams map[string]string,
-) *lua.LTable {
-	const op = "commands.handlers.execute-script.createParamsTable"
-
-	log := logger.With(sl.Op(op))
-
-	paramsTable := l.NewTable()
-	for _, param := range scriptParams {
-		log := log.With(slog.String("parameterName", param.Name))
-
-		stringParam, ok := messageParams[param.Name]
-		if !ok {
-			log.Info("parameter not found in message, setting to nil")
-			l.SetField(paramsTable, param.Name, lua.LNil)
-			continue
+		if res.Value == nil {
+			return nil
 		}
 
-		switch param.Type {
-		case TypeBool:
-			boolParam, err := strconv.ParseBool(stringParam)
-			if err != nil {
-				log.Warn(
-					"failed to parse bool parameter, setting to nil",
-					sl.Err(err),
-				)
-				l.SetField(paramsTable, param.Name, lua.LNil)
-			}
-
-			l.SetField(paramsTable, param.Name, lua.LBool(boolParam))
-
-		case TypeNumber:
-			numberParam, err := strconv.ParseFloat(stringParam, 64)
-			if err != nil {
-				log.Warn(
-					"failed to parse number parameter, setting to nil",
-					sl.Err(err),
-				)
-				l.SetField(paramsTable, param.Name, lua.LNil)
-			}
-
-			l.SetField(paramsTable, param.Name, lua.LNumber(numberParam))
-
-		case TypeString:
-			l.SetField(paramsTable, param.Name, lua.LString(stringParam))
-
-		default:
-			log.Warn("unknown type", slog.Any("parameter", param))
+		if err := resultPublisher.PublishResult(ctx, msg, res.Value); err != nil {
+			return fmt.Errorf("%s: failed to publish result: %w", op, err)
 		}
-	}
 
-	return paramsTable
+		return nil
+	}
 }
@@ -0,0 +1,60 @@
+package showNotification
+
+import (
+	"context"
+	"log/slog"
+	"smart-pc-agent/internal/lib/cross-platform/notify"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/commands"
+	"github.com/MaxRomanov007/smart-pc-go-lib/domain/models/message"
+	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
+)
+
+type Parameter struct {
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	Urgency string `json:"urgency"`
+	Icon    string `json:"icon"`
+}
+
+func New(log *slog.Logger) commands.CommandFunc {
+	return func(ctx context.Context, msg *message.Message) error {
+		const op = "commands.handlers.show-notification"
+
+		log := log.With(sl.Op(op), sl.MsgID(msg.Publish))
+
+		parameter, err := message.Parameter[Parameter](msg)
+		if err != nil {
+			log.Warn(
+				"failed to parse message parameter",
+				slog.Any("parameter", msg.Data.Parameter),
+				sl.Err(err),
+			)
+			return commands.Error("failed to get notification")
+		}
+
+		if parameter.Title == "" {
+			return commands.Error("notification title is empty")
+		}
+
+		urgency, err := notify.ParseUrgency(parameter.Urgency)
+		if err != nil {
+			log.Warn("invalid urgency", slog.String("urgency", parameter.Urgency), sl.Err(err))
+			return commands.Error("urgency must be low, normal or critical")
+		}
+
+		if err := notify.Send(notify.Notification{
+			Title:   parameter.Title,
+			Body:    parameter.Body,
+			Urgency: urgency,
+			Icon:    parameter.Icon,
+		}); err != nil {
+			log.Warn("failed to show notification", sl.Err(err))
+			return commands.Error("failed to show notification")
+		}
+
+		log.Info("notification shown")
+
+		return nil
+	}
+}
@@ -0,0 +1,64 @@
+package clipboard
+
+import (
+	"smart-pc-agent/internal/lib/cross-platform/clipboard"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+type Module struct{}
+
+func New() *Module {
+	return &Module{}
+}
+
+func (m *Module) Register(l *lua.LState, table *lua.LTable) {
+	l.SetField(table, "get", m.get(l))
+	l.SetField(table, "set", m.set(l))
+}
+
+func (m *Module) get(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		text, err := clipboard.Read()
+		if err != nil {
+			l.RaiseError("clipboard.get: %s", err.Error())
+			return 0
+		}
+
+		l.Push(lua.LString(text))
+		return 1
+	})
+}
+
+func (m *Module) set(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		if err := clipboard.Write(l.CheckString(1)); err != nil {
+			l.RaiseError("clipboard.set: %s", err.Error())
+		}
+		return 0
+	})
+}
+
+func (m *Module) Doc() luaApi.ModuleDoc {
+	return luaApi.ModuleDoc{
+		Description: "system clipboard text",
+		Functions: map[string]luaApi.FunctionDoc{
+			"get": {
+				Description: "get the clipboard text",
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeString, Description: "clipboard text, empty if there is no text"},
+				},
+				Example: `local url = spc.clipboard.get()
+if url:match("^https?://") then spc.process.start("firefox", { args = { url } }) end`,
+			},
+			"set": {
+				Description: "replace the clipboard content with text",
+				Params: []luaApi.ParamDoc{
+					{Name: "text", Type: luaApi.TypeString, Description: "text to copy"},
+				},
+				Example: `spc.clipboard.set(spc.params.code)`,
+			},
+		},
+	}
+}
@@ -0,0 +1,345 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"smart-pc-agent/internal/config"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+var (
+	ErrNoRoots      = errors.New("file system access is disabled: no roots configured")
+	ErrOutsideRoots = errors.New("path is outside of allowed roots")
+	ErrRoot         = errors.New("operation is not allowed on a root directory")
+)
+
+type Module struct {
+	roots []string
+}
+
+// New создаёт модуль, которому доступны только файлы внутри cfg.Roots.
+// Относительные пути в скриптах считаются от первого корня.
+func New(cfg config.ScriptsFS) *Module {
+	roots := make([]string, 0, len(cfg.Roots))
+	for _, root := range cfg.Roots {
+		if root == "" {
+			continue
+		}
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			continue
+		}
+		roots = append(roots, resolveSymlinks(abs))
+	}
+	return &Module{roots: roots}
+}
+
+func (m *Module) Register(l *lua.LState, table *lua.LTable) {
+	l.SetField(table, "read", m.read(l))
+	l.SetField(table, "write", m.write(l))
+	l.SetField(table, "list", m.list(l))
+	l.SetField(table, "stat", m.stat(l))
+	l.SetField(table, "mkdir", m.mkdir(l))
+	l.SetField(table, "remove", m.remove(l))
+	l.SetField(table, "move", m.move(l))
+}
+
+func (m *Module) read(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		path := m.mustResolve(l, l.CheckString(1))
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			l.RaiseError("fs.read: %s", err.Error())
+			return 0
+		}
+
+		l.Push(lua.LString(data))
+		return 1
+	})
+}
+
+func (m *Module) write(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		path := m.mustResolve(l, l.CheckString(1))
+		content := l.CheckString(2)
+		appendContent := l.OptBool(3, false)
+
+		flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		if appendContent {
+			flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		}
+
+		file, err := os.OpenFile(path, flags, 0o644)
+		if err != nil {
+			l.RaiseError("fs.write: %s", err.Error())
+			return 0
+		}
+
+		_, writeErr := file.WriteString(content)
+		closeErr := file.Close()
+		if err := errors.Join(writeErr, closeErr); err != nil {
+			l.RaiseError("fs.write: %s", err.Error())
+		}
+
+		return 0
+	})
+}
+
+func (m *Module) list(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		path := m.mustResolve(l, l.OptString(1, "."))
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			l.RaiseError("fs.list: %s", err.Error())
+			return 0
+		}
+
+		result := l.CreateTable(len(entries), 0)
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			result.Append(infoTable(l, info))
+		}
+
+		l.Push(result)
+		return 1
+	})
+}
+
+func (m *Module) stat(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		path := m.mustResolve(l, l.CheckString(1))
+
+		info, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			l.Push(lua.LNil)
+			return 1
+		}
+		if err != nil {
+			l.RaiseError("fs.stat: %s", err.Error())
+			return 0
+		}
+
+		l.Push(infoTable(l, info))
+		return 1
+	})
+}
+
+func (m *Module) mkdir(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		path := m.mustResolve(l, l.CheckString(1))
+
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			l.RaiseError("fs.mkdir: %s", err.Error())
+		}
+
+		return 0
+	})
+}
+
+func (m *Module) remove(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		path := m.mustResolveNotRoot(l, l.CheckString(1))
+		recursive := l.OptBool(2, false)
+
+		remove := os.Remove
+		if recursive {
+			remove = os.RemoveAll
+		}
+
+		if err := remove(path); err != nil {
+			l.RaiseError("fs.remove: %s", err.Error())
+		}
+
+		return 0
+	})
+}
+
+func (m *Module) move(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		from := m.mustResolveNotRoot(l, l.CheckString(1))
+		to := m.mustResolveNotRoot(l, l.CheckString(2))
+
+		if err := os.Rename(from, to); err != nil {
+			l.RaiseError("fs.move: %s", err.Error())
+		}
+
+		return 0
+	})
+}
+
+func (m *Module) mustResolve(l *lua.LState, path string) string {
+	resolved, err := m.resolve(path)
+	if err != nil {
+		l.RaiseError("fs: %s: %s", path, err.Error())
+	}
+	return resolved
+}
+
+func (m *Module) mustResolveNotRoot(l *lua.LState, path string) string {
+	resolved := m.mustResolve(l, path)
+	for _, root := range m.roots {
+		if resolveSymlinks(resolved) == root {
+			l.RaiseError("fs: %s: %s", path, ErrRoot.Error())
+		}
+	}
+	return resolved
+}
+
+// resolve приводит путь к абсолютному и проверяет, что он (с учётом
+// символических ссылок) находится внутри одного из разрешённых корней
+func (m *Module) resolve(path string) (string, error) {
+	if len(m.roots) == 0 {
+		return "", ErrNoRoots
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(m.roots[0], path)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	realPath := resolveSymlinks(abs)
+	for _, root := range m.roots {
+		if isWithin(root, realPath) {
+			return abs, nil
+		}
+	}
+
+	return "", ErrOutsideRoots
+}
+
+// resolveSymlinks раскрывает ссылки в самой длинной существующей части пути
+func resolveSymlinks(path string) string {
+	rest := ""
+	current := path
+	for {
+		resolved, err := filepath.EvalSymlinks(current)
+		if err == nil {
+			return filepath.Join(resolved, rest)
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return path
+		}
+		rest = filepath.Join(filepath.Base(current), rest)
+		current = parent
+	}
+}
+
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func infoTable(l *lua.LState, info fs.FileInfo) *lua.LTable {
+	table := l.NewTable()
+	l.SetField(table, "name", lua.LString(info.Name()))
+	l.SetField(table, "size", lua.LNumber(info.Size()))
+	l.SetField(table, "isDir", lua.LBool(info.IsDir()))
+	l.SetField(table, "mode", lua.LString(info.Mode().String()))
+	l.SetField(table, "modTime", lua.LNumber(info.ModTime().Unix()))
+	return table
+}
+
+func (m *Module) Doc() luaApi.ModuleDoc {
+	pathParam := luaApi.ParamDoc{
+		Name:        "path",
+		Type:        luaApi.TypeString,
+		Description: "path inside one of the allowed roots, relative paths start from the first root",
+	}
+	infoDescription := "table with fields name, size, isDir, mode and modTime (unix seconds)"
+
+	return luaApi.ModuleDoc{
+		Description: "sandboxed file system access, restricted to the configured root directories",
+		Functions: map[string]luaApi.FunctionDoc{
+			"read": {
+				Description: "read a whole file",
+				Params:      []luaApi.ParamDoc{pathParam},
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeString, Description: "file content"},
+				},
+				Example: `local text = spc.fs.read("notes.txt")`,
+			},
+			"write": {
+				Description: "write a file, creating it if needed",
+				Params: []luaApi.ParamDoc{
+					pathParam,
+					{
+						Name:        "content",
+						Type:        luaApi.TypeString,
+						Description: "data to write",
+					},
+					{
+						Name:        "append",
+						Type:        luaApi.TypeBoolean,
+						Description: "append to the end of the file instead of overwriting it",
+						Optional:    true,
+					},
+				},
+				Example: `spc.fs.write("log.txt", "done\n", true)`,
+			},
+			"list": {
+				Description: "list directory entries",
+				Params: []luaApi.ParamDoc{
+					{
+						Name:        "path",
+						Type:        luaApi.TypeString,
+						Description: "directory path, defaults to the first root",
+						Optional:    true,
+					},
+				},
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeTable, Description: "array of entries, each a " + infoDescription},
+				},
+				Example: `for _, e in ipairs(spc.fs.list("downloads")) do spc.log.info(e.name) end`,
+			},
+			"stat": {
+				Description: "get file information",
+				Params:      []luaApi.ParamDoc{pathParam},
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeTable, Description: infoDescription + ", or nil if the file does not exist"},
+				},
+			},
+			"mkdir": {
+				Description: "create a directory with all missing parents",
+				Params:      []luaApi.ParamDoc{pathParam},
+			},
+			"remove": {
+				Description: "remove a file or an empty directory",
+				Params: []luaApi.ParamDoc{
+					pathParam,
+					{
+						Name:        "recursive",
+						Type:        luaApi.TypeBoolean,
+						Description: "remove a directory with all its content",
+						Optional:    true,
+					},
+				},
+			},
+			"move": {
+				Description: "move or rename a file or directory",
+				Params: []luaApi.ParamDoc{
+					{Name: "from", Type: luaApi.TypeString, Description: "source path"},
+					{Name: "to", Type: luaApi.TypeString, Description: "destination path"},
+				},
+			},
+		},
+	}
+}
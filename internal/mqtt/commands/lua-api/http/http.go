@@ -0,0 +1,226 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"smart-pc-agent/internal/config"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	"strings"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+const maxRedirects = 10
+
+var (
+	ErrHostNotAllowed   = errors.New("host is not allowed")
+	ErrResponseTooLarge = errors.New("response body is too large")
+)
+
+type Module struct {
+	client          *http.Client
+	allowedHosts    []string
+	timeout         time.Duration
+	maxResponseSize int64
+}
+
+// New создаёт http-клиент для скриптов. Запросы разрешены только к хостам
+// из cfg.AllowedHosts: "host", "host:port" или "*.domain".
+func New(cfg config.ScriptsHTTP) *Module {
+	m := &Module{
+		allowedHosts:    cfg.AllowedHosts,
+		timeout:         cfg.Timeout,
+		maxResponseSize: cfg.MaxResponseSize,
+	}
+
+	m.client = &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if !m.isAllowed(req.URL) {
+				return fmt.Errorf("redirect to %s: %w", req.URL.Host, ErrHostNotAllowed)
+			}
+			return nil
+		},
+	}
+
+	return m
+}
+
+func (m *Module) Register(l *lua.LState, table *lua.LTable) {
+	l.SetField(table, "request", m.request(l))
+}
+
+func (m *Module) request(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		method := strings.ToUpper(l.CheckString(1))
+		rawURL := l.CheckString(2)
+		headers := l.OptTable(3, nil)
+		body := l.OptString(4, "")
+		timeout := m.timeout
+		// сравнение в секундах до перевода в Duration, чтобы огромные
+		// значения и inf не переполнили его; NaN не проходит ни одно условие
+		if seconds := float64(l.OptNumber(5, 0)); seconds > 0 && seconds < m.timeout.Seconds() {
+			timeout = time.Duration(seconds * float64(time.Second))
+		}
+
+		target, err := url.Parse(rawURL)
+		if err != nil {
+			l.RaiseError("http.request: invalid url: %s", err.Error())
+			return 0
+		}
+		if target.Scheme != "http" && target.Scheme != "https" {
+			l.RaiseError("http.request: unsupported scheme %q", target.Scheme)
+			return 0
+		}
+		if !m.isAllowed(target) {
+			l.RaiseError("http.request: %s: %s", target.Host, ErrHostNotAllowed.Error())
+			return 0
+		}
+
+		ctx := l.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		var reqBody io.Reader
+		if body != "" {
+			reqBody = strings.NewReader(body)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, target.String(), reqBody)
+		if err != nil {
+			l.RaiseError("http.request: %s", err.Error())
+			return 0
+		}
+		if headers != nil {
+			headers.ForEach(func(key lua.LValue, value lua.LValue) {
+				req.Header.Set(key.String(), value.String())
+			})
+		}
+
+		resp, err := m.client.Do(req)
+		if err != nil {
+			l.RaiseError("http.request: %s", err.Error())
+			return 0
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(io.LimitReader(resp.Body, m.maxResponseSize+1))
+		if err != nil {
+			l.RaiseError("http.request: failed to read body: %s", err.Error())
+			return 0
+		}
+		if int64(len(data)) > m.maxResponseSize {
+			l.RaiseError(
+				"http.request: %s: limit is %d bytes",
+				ErrResponseTooLarge.Error(),
+				m.maxResponseSize,
+			)
+			return 0
+		}
+
+		respHeaders := l.NewTable()
+		for name, values := range resp.Header {
+			l.SetField(respHeaders, name, lua.LString(strings.Join(values, ", ")))
+		}
+
+		result := l.NewTable()
+		l.SetField(result, "status", lua.LNumber(resp.StatusCode))
+		l.SetField(result, "headers", respHeaders)
+		l.SetField(result, "body", lua.LString(data))
+
+		l.Push(result)
+		return 1
+	})
+}
+
+func (m *Module) isAllowed(target *url.URL) bool {
+	host := strings.ToLower(target.Hostname())
+	port := target.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[target.Scheme]
+	}
+
+	for _, allowed := range m.allowedHosts {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+
+		allowedHost, allowedPort, err := net.SplitHostPort(allowed)
+		if err != nil {
+			allowedHost, allowedPort = strings.Trim(allowed, "[]"), ""
+		}
+		if allowedPort != "" && allowedPort != port {
+			continue
+		}
+
+		if suffix, ok := strings.CutPrefix(allowedHost, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == allowedHost {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *Module) Doc() luaApi.ModuleDoc {
+	return luaApi.ModuleDoc{
+		Description: "HTTP client, limited to the hosts allowed in the agent config",
+		Functions: map[string]luaApi.FunctionDoc{
+			"request": {
+				Description: "send an HTTP request and read the whole response",
+				Params: []luaApi.ParamDoc{
+					{
+						Name:        "method",
+						Type:        luaApi.TypeString,
+						Description: "HTTP method, e.g. GET or POST",
+					},
+					{
+						Name:        "url",
+						Type:        luaApi.TypeString,
+						Description: "http or https URL of an allowed host",
+					},
+					{
+						Name:        "headers",
+						Type:        luaApi.TypeTable,
+						Description: "request headers, name to value",
+						Optional:    true,
+					},
+					{
+						Name:        "body",
+						Type:        luaApi.TypeString,
+						Description: "request body",
+						Optional:    true,
+					},
+					{
+						Name:        "timeout",
+						Type:        luaApi.TypeNumber,
+						Description: "timeout in seconds, can not exceed the configured one",
+						Optional:    true,
+					},
+				},
+				Returns: []luaApi.ReturnDoc{
+					{
+						Type:        luaApi.TypeTable,
+						Description: "table with fields status (number), headers (table) and body (string)",
+					},
+				},
+				Example: `local r = spc.http.request("GET", "http://nas.local/api/status")
+if r.status == 200 then spc.log.info(r.body) end`,
+			},
+		},
+	}
+}
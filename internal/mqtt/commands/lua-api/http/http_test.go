@@ -0,0 +1,256 @@
+package http
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"smart-pc-agent/internal/config"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func newState(t *testing.T, cfg config.ScriptsHTTP) *lua.LState {
+	t.Helper()
+
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.MaxResponseSize == 0 {
+		cfg.MaxResponseSize = 1 << 20
+	}
+
+	l := lua.NewState()
+	t.Cleanup(l.Close)
+
+	table := l.NewTable()
+	New(cfg).Register(l, table)
+	l.SetGlobal("http", table)
+
+	return l
+}
+
+// request выполняет http.request(method, url, nil, nil, timeout) и возвращает
+// таблицу результата или ошибку скрипта
+func request(l *lua.LState, method, target string, timeout float64) (*lua.LTable, error) {
+	err := l.CallByParam(
+		lua.P{Fn: l.GetField(l.GetGlobal("http"), "request"), NRet: 1, Protect: true},
+		lua.LString(method),
+		lua.LString(target),
+		lua.LNil,
+		lua.LNil,
+		lua.LNumber(timeout),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := l.Get(-1)
+	l.Pop(1)
+	return result.(*lua.LTable), nil
+}
+
+func serverHost(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse server url: %v", err)
+	}
+	return serverURL.Host
+}
+
+func TestIsAllowed(t *testing.T) {
+	m := New(config.ScriptsHTTP{
+		AllowedHosts: []string{"nas.local", "printer.lan:8080", "*.example.com", " Home.Lan "},
+	})
+
+	tests := []struct {
+		url     string
+		allowed bool
+	}{
+		{"http://nas.local/api", true},
+		{"http://nas.local:9000/api", true},
+		{"https://NAS.local/", true},
+		{"http://printer.lan:8080/", true},
+		{"http://printer.lan/", false},
+		{"http://printer.lan:8081/", false},
+		{"http://api.example.com/", true},
+		{"http://a.b.example.com/", true},
+		{"http://example.com/", false},
+		{"http://badexample.com/", false},
+		{"http://home.lan/", true},
+		{"http://other.local/", false},
+	}
+
+	for _, tt := range tests {
+		target, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", tt.url, err)
+		}
+		if got := m.isAllowed(target); got != tt.allowed {
+			t.Errorf("isAllowed(%q) = %v, want %v", tt.url, got, tt.allowed)
+		}
+	}
+}
+
+func TestRequestAllowList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "yes")
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	host := serverHost(t, server)
+	hostname, _, _ := strings.Cut(host, ":")
+
+	tests := []struct {
+		name         string
+		allowedHosts []string
+		allowed      bool
+	}{
+		{"host", []string{hostname}, true},
+		{"host and port", []string{host}, true},
+		{"other port", []string{hostname + ":1"}, false},
+		{"wildcard", []string{"*.example.com"}, false},
+		{"empty", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newState(t, config.ScriptsHTTP{AllowedHosts: tt.allowedHosts})
+
+			result, err := request(l, "get", server.URL+"/status", 0)
+			if !tt.allowed {
+				if err == nil || !strings.Contains(err.Error(), ErrHostNotAllowed.Error()) {
+					t.Fatalf("expected %q error, got %v", ErrHostNotAllowed, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+
+			if status := l.GetField(result, "status"); status != lua.LNumber(http.StatusOK) {
+				t.Errorf("status = %v, want 200", status)
+			}
+			if body := l.GetField(result, "body"); body != lua.LString("hello") {
+				t.Errorf("body = %v, want hello", body)
+			}
+			headers := l.GetField(result, "headers").(*lua.LTable)
+			if header := l.GetField(headers, "X-Test"); header != lua.LString("yes") {
+				t.Errorf("X-Test header = %v, want yes", header)
+			}
+		})
+	}
+}
+
+func TestRequestRedirectToDisallowedHost(t *testing.T) {
+	var targetHit atomic.Bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		targetHit.Store(true)
+	}))
+	defer target.Close()
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL+"/secret", http.StatusFound)
+	}))
+	defer origin.Close()
+
+	// разрешён только исходный сервер, у цели другой порт
+	l := newState(t, config.ScriptsHTTP{AllowedHosts: []string{serverHost(t, origin)}})
+
+	_, err := request(l, "GET", origin.URL, 0)
+	if err == nil || !strings.Contains(err.Error(), ErrHostNotAllowed.Error()) {
+		t.Fatalf("expected %q error, got %v", ErrHostNotAllowed, err)
+	}
+	if targetHit.Load() {
+		t.Error("redirect target was requested")
+	}
+}
+
+func TestRequestResponseSizeCap(t *testing.T) {
+	const limit = 1000
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size := limit
+		if r.URL.Path == "/large" {
+			size = limit + 1
+		}
+		_, _ = w.Write([]byte(strings.Repeat("a", size)))
+	}))
+	defer server.Close()
+
+	l := newState(t, config.ScriptsHTTP{
+		AllowedHosts:    []string{serverHost(t, server)},
+		MaxResponseSize: limit,
+	})
+
+	result, err := request(l, "GET", server.URL+"/exact", 0)
+	if err != nil {
+		t.Fatalf("request at the limit failed: %v", err)
+	}
+	if body := l.GetField(result, "body").String(); len(body) != limit {
+		t.Errorf("body length = %d, want %d", len(body), limit)
+	}
+
+	_, err = request(l, "GET", server.URL+"/large", 0)
+	if err == nil || !strings.Contains(err.Error(), ErrResponseTooLarge.Error()) {
+		t.Fatalf("expected %q error, got %v", ErrResponseTooLarge, err)
+	}
+}
+
+func TestRequestTimeoutClamp(t *testing.T) {
+	const configured = 200 * time.Millisecond
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	l := newState(t, config.ScriptsHTTP{
+		AllowedHosts: []string{serverHost(t, server)},
+		Timeout:      configured,
+	})
+
+	// запрос должен длиться настроенный таймаут, а не завершаться сразу
+	// из-за переполнения и не ждать ответа сервера
+	tests := []struct {
+		name    string
+		timeout float64
+		min     time.Duration
+		max     time.Duration
+	}{
+		{"default", 0, configured, 2 * time.Second},
+		{"above configured", 3600, configured, 2 * time.Second},
+		{"overflowing", 1e300, configured, 2 * time.Second},
+		{"infinite", math.Inf(1), configured, 2 * time.Second},
+		{"nan", math.NaN(), configured, 2 * time.Second},
+		{"below configured", 0.02, 0, configured},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started := time.Now()
+			_, err := request(l, "GET", server.URL, tt.timeout)
+			elapsed := time.Since(started)
+
+			if err == nil {
+				t.Fatal("expected timeout error")
+			}
+			if elapsed < tt.min {
+				t.Errorf("request failed after %s, want at least %s: %v", elapsed, tt.min, err)
+			}
+			if elapsed >= tt.max {
+				t.Errorf("request took %s, want less than %s", elapsed, tt.max)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package input
+
+import (
+	"context"
+	"fmt"
+	"smart-pc-agent/internal/lib/cross-platform/input"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	"unicode/utf8"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ограничения на объём синтезируемого ввода за один вызов
+const (
+	maxTextLength  = 4096
+	maxClickCount  = 20
+	maxScrollSteps = 100
+)
+
+type Module struct{}
+
+func New() *Module {
+	return &Module{}
+}
+
+func (m *Module) Register(l *lua.LState, table *lua.LTable) {
+	l.SetField(table, "key", m.key(l))
+	l.SetField(table, "type", m.typeText(l))
+	l.SetField(table, "move", m.move(l))
+	l.SetField(table, "moveBy", m.moveBy(l))
+	l.SetField(table, "click", m.click(l))
+	l.SetField(table, "scroll", m.scroll(l))
+}
+
+func (m *Module) key(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		if err := input.KeyTap(luaContext(l), l.CheckString(1)); err != nil {
+			l.RaiseError("input.key: %s", err.Error())
+		}
+		return 0
+	})
+}
+
+func (m *Module) typeText(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		text := l.CheckString(1)
+		if utf8.RuneCountInString(text) > maxTextLength {
+			l.ArgError(1, fmt.Sprintf("text is longer than %d characters", maxTextLength))
+			return 0
+		}
+
+		if err := input.Type(luaContext(l), text); err != nil {
+			l.RaiseError("input.type: %s", err.Error())
+		}
+		return 0
+	})
+}
+
+func (m *Module) move(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		if err := input.MouseMove(luaContext(l), l.CheckInt(1), l.CheckInt(2)); err != nil {
+			l.RaiseError("input.move: %s", err.Error())
+		}
+		return 0
+	})
+}
+
+func (m *Module) moveBy(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		if err := input.MouseMoveRelative(luaContext(l), l.CheckInt(1), l.CheckInt(2)); err != nil {
+			l.RaiseError("input.moveBy: %s", err.Error())
+		}
+		return 0
+	})
+}
+
+func (m *Module) click(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		button, err := input.ParseButton(l.OptString(1, ""))
+		if err != nil {
+			l.ArgError(1, "button must be left, right or middle")
+			return 0
+		}
+
+		count := l.OptInt(2, 1)
+		if count > maxClickCount {
+			l.ArgError(2, fmt.Sprintf("count must not exceed %d", maxClickCount))
+			return 0
+		}
+
+		if err := input.Click(luaContext(l), button, count); err != nil {
+			l.RaiseError("input.click: %s", err.Error())
+		}
+		return 0
+	})
+}
+
+func (m *Module) scroll(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		dy, dx := l.CheckInt(1), l.OptInt(2, 0)
+		if dy < -maxScrollSteps || dy > maxScrollSteps {
+			l.ArgError(1, fmt.Sprintf("dy must be between -%d and %d", maxScrollSteps, maxScrollSteps))
+			return 0
+		}
+		if dx < -maxScrollSteps || dx > maxScrollSteps {
+			l.ArgError(2, fmt.Sprintf("dx must be between -%d and %d", maxScrollSteps, maxScrollSteps))
+			return 0
+		}
+
+		if err := input.Scroll(luaContext(l), dx, dy); err != nil {
+			l.RaiseError("input.scroll: %s", err.Error())
+		}
+		return 0
+	})
+}
+
+func luaContext(l *lua.LState) context.Context {
+	if ctx := l.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+func (m *Module) Doc() luaApi.ModuleDoc {
+	return luaApi.ModuleDoc{
+		Description: "keyboard and mouse synthesis; on Linux needs xdotool (X11) or ydotool (Wayland), " +
+			"on macOS the Accessibility permission",
+		Functions: map[string]luaApi.FunctionDoc{
+			"key": {
+				Description: "press a key combination, keys are pressed in order and released in reverse",
+				Params: []luaApi.ParamDoc{
+					{
+						Name: "combo",
+						Type: luaApi.TypeString,
+						Description: "keys joined with \"+\": ctrl, shift, alt, super (win/cmd), enter, tab, " +
+							"escape, space, backspace, delete, insert, home, end, pageup, pagedown, " +
+							"up, down, left, right, f1-f12 or a single character",
+					},
+				},
+				Example: `spc.input.key("ctrl+shift+t")`,
+			},
+			"type": {
+				Description: "type text as if it was entered from the keyboard",
+				Params: []luaApi.ParamDoc{
+					{
+						Name:        "text",
+						Type:        luaApi.TypeString,
+						Description: "text to type, at most 4096 characters",
+					},
+				},
+				Example: `spc.input.type(spc.params.query)
+spc.input.key("enter")`,
+			},
+			"move": {
+				Description: "move the mouse cursor to screen coordinates",
+				Params: []luaApi.ParamDoc{
+					{Name: "x", Type: luaApi.TypeNumber, Description: "pixels from the left edge"},
+					{Name: "y", Type: luaApi.TypeNumber, Description: "pixels from the top edge"},
+				},
+			},
+			"moveBy": {
+				Description: "move the mouse cursor relative to its current position",
+				Params: []luaApi.ParamDoc{
+					{Name: "dx", Type: luaApi.TypeNumber, Description: "horizontal offset in pixels"},
+					{Name: "dy", Type: luaApi.TypeNumber, Description: "vertical offset in pixels"},
+				},
+			},
+			"click": {
+				Description: "click a mouse button at the current cursor position",
+				Params: []luaApi.ParamDoc{
+					{
+						Name:        "button",
+						Type:        luaApi.TypeString,
+						Description: "\"left\", \"right\" or \"middle\", defaults to left",
+						Optional:    true,
+					},
+					{
+						Name:        "count",
+						Type:        luaApi.TypeNumber,
+						Description: "number of clicks, 2 for a double click, at most 20",
+						Optional:    true,
+					},
+				},
+				Example: `spc.input.move(960, 540)
+spc.input.click("left", 2)`,
+			},
+			"scroll": {
+				Description: "scroll the mouse wheel",
+				Params: []luaApi.ParamDoc{
+					{Name: "dy", Type: luaApi.TypeNumber, Description: "steps down, negative scrolls up, at most 100"},
+					{
+						Name:        "dx",
+						Type:        luaApi.TypeNumber,
+						Description: "steps right, negative scrolls left, at most 100",
+						Optional:    true,
+					},
+				},
+				Example: `spc.input.scroll(5)`,
+			},
+		},
+	}
+}
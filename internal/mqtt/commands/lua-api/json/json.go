@@ -0,0 +1,133 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	luaJson "smart-pc-agent/internal/lib/lua-json"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+type Module struct{}
+
+func New() *Module {
+	return &Module{}
+}
+
+func (m *Module) Register(l *lua.LState, table *lua.LTable) {
+	l.SetField(table, "encode", m.encode(l))
+	l.SetField(table, "decode", m.decode(l))
+	l.SetField(table, "array", m.array(l))
+	l.SetField(table, "null", luaJson.Null(l))
+}
+
+func (m *Module) encode(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		value := l.Get(1)
+		pretty := l.OptBool(2, false)
+
+		data, err := luaJson.Marshal(value)
+		if err != nil {
+			l.RaiseError("json.encode: %s", err.Error())
+			return 0
+		}
+
+		if pretty {
+			var buf bytes.Buffer
+			if err := json.Indent(&buf, data, "", "  "); err != nil {
+				l.RaiseError("json.encode: %s", err.Error())
+				return 0
+			}
+			data = buf.Bytes()
+		}
+
+		l.Push(lua.LString(data))
+		return 1
+	})
+}
+
+func (m *Module) decode(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		value, err := luaJson.Unmarshal(l, []byte(l.CheckString(1)))
+		if err != nil {
+			l.RaiseError("json.decode: %s", err.Error())
+			return 0
+		}
+
+		l.Push(value)
+		return 1
+	})
+}
+
+func (m *Module) array(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		table := l.OptTable(1, l.NewTable())
+		luaJson.MarkArray(l, table)
+
+		l.Push(table)
+		return 1
+	})
+}
+
+func (m *Module) Doc() luaApi.ModuleDoc {
+	return luaApi.ModuleDoc{
+		Description: "JSON encoding and decoding; tables with keys 1..n are arrays, " +
+			"other tables are objects, JSON null is spc.json.null",
+		Functions: map[string]luaApi.FunctionDoc{
+			"encode": {
+				Description: "encode a value to JSON",
+				Params: []luaApi.ParamDoc{
+					{
+						Name:        "value",
+						Type:        luaApi.TypeAny,
+						Description: "nil, boolean, number, string, table or spc.json.null",
+					},
+					{
+						Name:        "pretty",
+						Type:        luaApi.TypeBoolean,
+						Description: "indent the output",
+						Optional:    true,
+					},
+				},
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeString, Description: "JSON text"},
+				},
+				Example: `spc.json.encode({ volume = 20, tags = { "a", "b" } })`,
+			},
+			"decode": {
+				Description: "decode JSON text, raises an error if it is invalid",
+				Params: []luaApi.ParamDoc{
+					{Name: "text", Type: luaApi.TypeString, Description: "JSON text"},
+				},
+				Returns: []luaApi.ReturnDoc{
+					{
+						Type:        luaApi.TypeAny,
+						Description: "decoded value, arrays keep encoding as arrays even when empty",
+					},
+				},
+				Example: `local data = spc.json.decode(spc.params.payload)`,
+			},
+			"array": {
+				Description: "mark a table to be encoded as an array even when it is empty",
+				Params: []luaApi.ParamDoc{
+					{
+						Name:        "table",
+						Type:        luaApi.TypeTable,
+						Description: "table to mark, a new one is created if omitted",
+						Optional:    true,
+					},
+				},
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeTable, Description: "the marked table"},
+				},
+			},
+		},
+		Fields: map[string]luaApi.FieldDoc{
+			"null": {
+				Type:        luaApi.TypeAny,
+				Description: "value representing JSON null",
+			},
+		},
+	}
+}
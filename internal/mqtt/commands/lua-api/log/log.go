@@ -24,28 +24,36 @@ func (m *Module) Register(l *lua.LState, table *lua.LTable) {
 
 func (m *Module) logDebug(l *lua.LState) lua.LValue {
 	return l.NewFunction(func(l *lua.LState) int {
-		m.log.Debug(l.Get(-1).String())
+		message := l.Get(-1).String()
+		m.log.Debug(message)
+		luaApi.CaptureLog(l, "debug", message)
 		return 0
 	})
 }
 
 func (m *Module) logInfo(l *lua.LState) lua.LValue {
 	return l.NewFunction(func(l *lua.LState) int {
-		m.log.Info(l.Get(-1).String())
+		message := l.Get(-1).String()
+		m.log.Info(message)
+		luaApi.CaptureLog(l, "info", message)
 		return 0
 	})
 }
 
 func (m *Module) logWarn(l *lua.LState) lua.LValue {
 	return l.NewFunction(func(l *lua.LState) int {
-		m.log.Warn(l.Get(-1).String())
+		message := l.Get(-1).String()
+		m.log.Warn(message)
+		luaApi.CaptureLog(l, "warn", message)
 		return 0
 	})
 }
 
 func (m *Module) logError(l *lua.LState) lua.LValue {
 	return l.NewFunction(func(l *lua.LState) int {
-		m.log.Error(l.Get(-1).String())
+		message := l.Get(-1).String()
+		m.log.Error(message)
+		luaApi.CaptureLog(l, "error", message)
 		return 0
 	})
 }
@@ -0,0 +1,47 @@
+package media
+
+import (
+	"smart-pc-agent/internal/lib/cross-platform/mediactl"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+type Module struct{}
+
+func New() *Module {
+	return &Module{}
+}
+
+func (m *Module) Register(l *lua.LState, table *lua.LTable) {
+	l.SetField(table, "playPause", action(l, "media.playPause", mediactl.PlayPause))
+	l.SetField(table, "next", action(l, "media.next", mediactl.NextTrack))
+	l.SetField(table, "prev", action(l, "media.prev", mediactl.PrevTrack))
+}
+
+func action(l *lua.LState, name string, fn func() error) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		if err := fn(); err != nil {
+			l.RaiseError("%s: %s", name, err.Error())
+		}
+		return 0
+	})
+}
+
+func (m *Module) Doc() luaApi.ModuleDoc {
+	return luaApi.ModuleDoc{
+		Description: "media playback control",
+		Functions: map[string]luaApi.FunctionDoc{
+			"playPause": {
+				Description: "toggle play/pause of the current player",
+				Example:     `spc.media.playPause()`,
+			},
+			"next": {
+				Description: "skip to the next track",
+			},
+			"prev": {
+				Description: "go back to the previous track",
+			},
+		},
+	}
+}
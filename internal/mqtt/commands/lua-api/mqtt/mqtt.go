@@ -0,0 +1,172 @@
+package mqtt
+
+import (
+	"context"
+	"fmt"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	luaJson "smart-pc-agent/internal/lib/lua-json"
+	"strings"
+	"sync"
+
+	"github.com/eclipse/paho.golang/paho"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const maxPayloadSize = 256 * 1024
+
+// reservedTopics используются самим агентом, скрипты не могут в них писать
+var reservedTopics = []string{"state", "status", "command", "log"}
+
+type Publisher interface {
+	Publish(ctx context.Context, publish *paho.Publish) (*paho.PublishResponse, error)
+}
+
+type Module struct {
+	mu        sync.RWMutex
+	publisher Publisher
+	prefix    string
+}
+
+// New создаёт модуль без соединения, чтобы он был в реестре с самого
+// запуска; publish работает после Connect
+func New() *Module {
+	return &Module{}
+}
+
+// Connect задаёт соединение, через которое скрипты публикуют сообщения
+func (m *Module) Connect(publisher Publisher, pcID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.publisher = publisher
+	m.prefix = fmt.Sprintf("pcs/%s/", pcID)
+}
+
+func (m *Module) connection() (Publisher, string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.publisher, m.prefix
+}
+
+func (m *Module) Register(l *lua.LState, table *lua.LTable) {
+	l.SetField(table, "publish", m.publish(l))
+}
+
+func (m *Module) publish(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		topic := l.CheckString(1)
+		payload := l.CheckAny(2)
+		options := l.OptTable(3, nil)
+
+		if err := validateTopic(topic); err != nil {
+			l.ArgError(1, err.Error())
+			return 0
+		}
+
+		var data []byte
+		if text, ok := payload.(lua.LString); ok {
+			data = []byte(text)
+		} else {
+			encoded, err := luaJson.Marshal(payload)
+			if err != nil {
+				l.RaiseError("mqtt.publish: payload is not serializable: %s", err.Error())
+				return 0
+			}
+			data = encoded
+		}
+		if len(data) > maxPayloadSize {
+			l.RaiseError("mqtt.publish: payload exceeds %d bytes", maxPayloadSize)
+			return 0
+		}
+
+		qos, retain := 1, false
+		if options != nil {
+			if value, ok := l.GetField(options, "qos").(lua.LNumber); ok {
+				qos = int(value)
+			}
+			retain = lua.LVAsBool(l.GetField(options, "retain"))
+		}
+		if qos < 0 || qos > 2 {
+			l.ArgError(3, "qos must be 0, 1 or 2")
+			return 0
+		}
+
+		publisher, prefix := m.connection()
+		if publisher == nil {
+			l.RaiseError("mqtt.publish: not connected to the broker")
+			return 0
+		}
+
+		ctx := l.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		if _, err := publisher.Publish(ctx, &paho.Publish{
+			QoS:     byte(qos),
+			Retain:  retain,
+			Topic:   prefix + topic,
+			Payload: data,
+		}); err != nil {
+			l.RaiseError("mqtt.publish: %s", err.Error())
+		}
+
+		return 0
+	})
+}
+
+func validateTopic(topic string) error {
+	if topic == "" {
+		return fmt.Errorf("topic must not be empty")
+	}
+	if strings.ContainsAny(topic, "+#\x00") {
+		return fmt.Errorf("topic must not contain wildcards")
+	}
+
+	for _, level := range strings.Split(topic, "/") {
+		if level == "" {
+			return fmt.Errorf("topic must not contain empty levels")
+		}
+	}
+
+	first, _, _ := strings.Cut(topic, "/")
+	for _, reserved := range reservedTopics {
+		if first == reserved {
+			return fmt.Errorf("topic %q is reserved by the agent", reserved)
+		}
+	}
+
+	return nil
+}
+
+func (m *Module) Doc() luaApi.ModuleDoc {
+	return luaApi.ModuleDoc{
+		Description: "publishing custom events to the broker under pcs/<pc id>/",
+		Functions: map[string]luaApi.FunctionDoc{
+			"publish": {
+				Description: "publish a message to pcs/<pc id>/<topic>; " +
+					"topics state, status, command and log are reserved",
+				Params: []luaApi.ParamDoc{
+					{
+						Name:        "topic",
+						Type:        luaApi.TypeString,
+						Description: "sub-topic without wildcards, e.g. events/backup",
+					},
+					{
+						Name:        "payload",
+						Type:        luaApi.TypeAny,
+						Description: "string sent as is, any other value is encoded to JSON",
+					},
+					{
+						Name:        "options",
+						Type:        luaApi.TypeTable,
+						Description: "table with optional fields qos (0, 1 or 2, default 1) and retain (default false)",
+						Optional:    true,
+					},
+				},
+				Example: `spc.mqtt.publish("events/backup", { status = "done", files = 42 })`,
+			},
+		},
+	}
+}
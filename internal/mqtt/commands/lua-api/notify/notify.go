@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"smart-pc-agent/internal/lib/cross-platform/notify"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+type Module struct{}
+
+func New() *Module {
+	return &Module{}
+}
+
+func (m *Module) Register(l *lua.LState, table *lua.LTable) {
+	l.SetField(table, "send", m.send(l))
+}
+
+func (m *Module) send(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		n := notify.Notification{
+			Title:   l.CheckString(1),
+			Body:    l.OptString(2, ""),
+			Urgency: notify.Normal,
+		}
+
+		if options := l.OptTable(3, nil); options != nil {
+			if urgency, ok := l.GetField(options, "urgency").(lua.LString); ok {
+				parsed, err := notify.ParseUrgency(string(urgency))
+				if err != nil {
+					l.ArgError(3, "urgency must be low, normal or critical")
+					return 0
+				}
+				n.Urgency = parsed
+			}
+			if icon, ok := l.GetField(options, "icon").(lua.LString); ok {
+				n.Icon = string(icon)
+			}
+		}
+
+		if err := notify.Send(n); err != nil {
+			l.RaiseError("notify.send: %s", err.Error())
+		}
+		return 0
+	})
+}
+
+func (m *Module) Doc() luaApi.ModuleDoc {
+	return luaApi.ModuleDoc{
+		Description: "desktop notifications",
+		Functions: map[string]luaApi.FunctionDoc{
+			"send": {
+				Description: "show a notification on the desktop of the logged in user",
+				Params: []luaApi.ParamDoc{
+					{
+						Name:        "title",
+						Type:        luaApi.TypeString,
+						Description: "notification title, must not be empty",
+					},
+					{
+						Name:        "body",
+						Type:        luaApi.TypeString,
+						Description: "notification text",
+						Optional:    true,
+					},
+					{
+						Name: "options",
+						Type: luaApi.TypeTable,
+						Description: "table with optional fields urgency (\"low\", \"normal\" or \"critical\") " +
+							"and icon (icon name on Linux or absolute path to an image; ignored on macOS)",
+						Optional: true,
+					},
+				},
+				Example: `spc.notify.send("Backup", "finished in " .. spc.time.since(start) .. " s", { urgency = "low" })`,
+			},
+		},
+	}
+}
@@ -0,0 +1,20 @@
+//go:build windows
+
+package process
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+const (
+	createNewProcessGroup = 0x00000200
+	detachedProcess       = 0x00000008
+)
+
+// detach запускает процесс без консоли агента в отдельной группе процессов
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: createNewProcessGroup | detachedProcess,
+	}
+}
@@ -0,0 +1,305 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"smart-pc-agent/internal/config"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	"strings"
+
+	psProcess "github.com/shirou/gopsutil/v4/process"
+	lua "github.com/yuin/gopher-lua"
+)
+
+type Module struct {
+	maxOutputSize int64
+}
+
+func New(cfg config.ScriptsProcess) *Module {
+	return &Module{maxOutputSize: cfg.MaxOutputSize}
+}
+
+func (m *Module) Register(l *lua.LState, table *lua.LTable) {
+	l.SetField(table, "run", m.run(l))
+	l.SetField(table, "start", m.start(l))
+	l.SetField(table, "list", m.list(l))
+	l.SetField(table, "kill", m.kill(l))
+}
+
+func (m *Module) run(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		ctx := l.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		cmd := exec.CommandContext(ctx, l.CheckString(1))
+		applyOptions(l, cmd, l.OptTable(2, nil))
+
+		stdout := &limitedBuffer{limit: m.maxOutputSize}
+		stderr := &limitedBuffer{limit: m.maxOutputSize}
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+
+		exitCode := 0
+		if err := cmd.Run(); err != nil {
+			exitErr, ok := errors.AsType[*exec.ExitError](err)
+			if !ok || ctx.Err() != nil {
+				l.RaiseError("process.run: %s", err.Error())
+				return 0
+			}
+			exitCode = exitErr.ExitCode()
+		}
+
+		result := l.NewTable()
+		l.SetField(result, "exitCode", lua.LNumber(exitCode))
+		l.SetField(result, "stdout", lua.LString(stdout.buf.String()))
+		l.SetField(result, "stderr", lua.LString(stderr.buf.String()))
+		l.SetField(result, "truncated", lua.LBool(stdout.truncated || stderr.truncated))
+
+		l.Push(result)
+		return 1
+	})
+}
+
+// limitedBuffer сохраняет первые limit байт вывода и отбрасывает остальное,
+// не останавливая процесс; limit 0 — без ограничения
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int64
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.limit <= 0 {
+		return b.buf.Write(p)
+	}
+
+	remaining := b.limit - int64(b.buf.Len())
+	if remaining < int64(len(p)) {
+		b.truncated = true
+		b.buf.Write(p[:max(remaining, 0)])
+		return len(p), nil
+	}
+
+	return b.buf.Write(p)
+}
+
+func (m *Module) start(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		cmd := exec.Command(l.CheckString(1))
+		applyOptions(l, cmd, l.OptTable(2, nil))
+		detach(cmd)
+
+		if err := cmd.Start(); err != nil {
+			l.RaiseError("process.start: %s", err.Error())
+			return 0
+		}
+
+		pid := cmd.Process.Pid
+		// процесс живёт дольше скрипта, Wait нужен только чтобы не оставлять зомби
+		go func() { _ = cmd.Wait() }()
+
+		l.Push(lua.LNumber(pid))
+		return 1
+	})
+}
+
+func (m *Module) list(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		nameFilter := l.OptString(1, "")
+
+		processes, err := psProcess.Processes()
+		if err != nil {
+			l.RaiseError("process.list: %s", err.Error())
+			return 0
+		}
+
+		result := l.NewTable()
+		for _, p := range processes {
+			name, err := p.Name()
+			if err != nil {
+				continue
+			}
+			if nameFilter != "" && !strings.EqualFold(name, nameFilter) {
+				continue
+			}
+
+			item := l.NewTable()
+			l.SetField(item, "pid", lua.LNumber(p.Pid))
+			l.SetField(item, "name", lua.LString(name))
+			if ppid, err := p.Ppid(); err == nil {
+				l.SetField(item, "ppid", lua.LNumber(ppid))
+			}
+			if cmdline, err := p.Cmdline(); err == nil {
+				l.SetField(item, "cmdline", lua.LString(cmdline))
+			}
+			result.Append(item)
+		}
+
+		l.Push(result)
+		return 1
+	})
+}
+
+func (m *Module) kill(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		switch target := l.CheckAny(1).(type) {
+		case lua.LNumber:
+			p, err := psProcess.NewProcess(int32(target))
+			if err != nil {
+				l.RaiseError("process.kill: %s", err.Error())
+				return 0
+			}
+			if err := p.Kill(); err != nil {
+				l.RaiseError("process.kill: %s", err.Error())
+				return 0
+			}
+			l.Push(lua.LNumber(1))
+
+		case lua.LString:
+			killed, err := killByName(string(target))
+			if err != nil {
+				l.RaiseError("process.kill: %s", err.Error())
+				return 0
+			}
+			l.Push(lua.LNumber(killed))
+
+		default:
+			l.ArgError(1, "pid (number) or process name (string) expected")
+			return 0
+		}
+
+		return 1
+	})
+}
+
+func killByName(name string) (int, error) {
+	processes, err := psProcess.Processes()
+	if err != nil {
+		return 0, err
+	}
+
+	killed := 0
+	errs := make([]error, 0)
+	for _, p := range processes {
+		processName, err := p.Name()
+		if err != nil || !strings.EqualFold(processName, name) {
+			continue
+		}
+		if err := p.Kill(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		killed++
+	}
+
+	if killed == 0 && len(errs) > 0 {
+		return 0, errors.Join(errs...)
+	}
+	return killed, nil
+}
+
+// applyOptions заполняет cmd из таблицы {args, env, dir, stdin}
+func applyOptions(l *lua.LState, cmd *exec.Cmd, options *lua.LTable) {
+	if options == nil {
+		return
+	}
+
+	if args, ok := l.GetField(options, "args").(*lua.LTable); ok {
+		for i := 1; i <= args.Len(); i++ {
+			cmd.Args = append(cmd.Args, args.RawGetInt(i).String())
+		}
+	}
+
+	if env, ok := l.GetField(options, "env").(*lua.LTable); ok {
+		cmd.Env = os.Environ()
+		env.ForEach(func(key lua.LValue, value lua.LValue) {
+			cmd.Env = append(cmd.Env, key.String()+"="+value.String())
+		})
+	}
+
+	if dir, ok := l.GetField(options, "dir").(lua.LString); ok {
+		cmd.Dir = string(dir)
+	}
+
+	if stdin, ok := l.GetField(options, "stdin").(lua.LString); ok {
+		cmd.Stdin = strings.NewReader(string(stdin))
+	}
+}
+
+func (m *Module) Doc() luaApi.ModuleDoc {
+	commandParam := luaApi.ParamDoc{
+		Name:        "command",
+		Type:        luaApi.TypeString,
+		Description: "executable name or path",
+	}
+	optionsParam := luaApi.ParamDoc{
+		Name: "options",
+		Type: luaApi.TypeTable,
+		Description: "table with optional fields args (array of strings), " +
+			"env (table of variables added to the agent environment), dir (working directory) " +
+			"and stdin (string)",
+		Optional: true,
+	}
+
+	return luaApi.ModuleDoc{
+		Description: "process management",
+		Functions: map[string]luaApi.FunctionDoc{
+			"run": {
+				Description: "run a process and wait for it to finish; it is killed when the script " +
+					"is cancelled. Output above the configured size is dropped and truncated is set",
+				Params: []luaApi.ParamDoc{commandParam, optionsParam},
+				Returns: []luaApi.ReturnDoc{
+					{
+						Type:        luaApi.TypeTable,
+						Description: "table with fields exitCode, stdout, stderr and truncated",
+					},
+				},
+				Example: `local r = spc.process.run("git", { args = { "pull" }, dir = "C:/projects/app" })`,
+			},
+			"start": {
+				Description: "start a detached process that keeps running after the script finishes",
+				Params:      []luaApi.ParamDoc{commandParam, optionsParam},
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeNumber, Description: "process id"},
+				},
+				Example: `spc.process.start("steam")`,
+			},
+			"list": {
+				Description: "list running processes",
+				Params: []luaApi.ParamDoc{
+					{
+						Name:        "name",
+						Type:        luaApi.TypeString,
+						Description: "return only processes with this name (case-insensitive)",
+						Optional:    true,
+					},
+				},
+				Returns: []luaApi.ReturnDoc{
+					{
+						Type:        luaApi.TypeTable,
+						Description: "array of tables with fields pid, name, ppid and cmdline",
+					},
+				},
+			},
+			"kill": {
+				Description: "kill a process by pid or all processes with the given name",
+				Params: []luaApi.ParamDoc{
+					{
+						Name:        "target",
+						Type:        luaApi.TypeAny,
+						Description: "pid (number) or process name (string)",
+					},
+				},
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeNumber, Description: "number of killed processes"},
+				},
+				Example: `spc.process.kill("notepad.exe")`,
+			},
+		},
+	}
+}
@@ -0,0 +1,73 @@
+package result
+
+import (
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+const registryKey = "spc.result"
+
+type Module struct{}
+
+func New() *Module {
+	return &Module{}
+}
+
+func (m *Module) Register(l *lua.LState, table *lua.LTable) {
+	l.SetField(table, "set", m.set(l))
+	l.SetField(table, "get", m.get(l))
+}
+
+func (m *Module) set(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		Set(l, l.Get(1))
+		return 0
+	})
+}
+
+func (m *Module) get(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		l.Push(Get(l))
+		return 1
+	})
+}
+
+// Set сохраняет результат выполнения в реестре lua-состояния
+func Set(l *lua.LState, value lua.LValue) {
+	l.G.Registry.RawSetString(registryKey, value)
+}
+
+// Get возвращает результат, сохранённый через spc.result.set
+func Get(l *lua.LState) lua.LValue {
+	return l.G.Registry.RawGetString(registryKey)
+}
+
+func (m *Module) Doc() luaApi.ModuleDoc {
+	return luaApi.ModuleDoc{
+		Description: "command result, sent to the dashboard as JSON after the script finishes; " +
+			"a value returned from the script takes precedence",
+		Functions: map[string]luaApi.FunctionDoc{
+			"set": {
+				Description: "set the command result",
+				Params: []luaApi.ParamDoc{
+					{
+						Name:        "value",
+						Type:        luaApi.TypeAny,
+						Description: "JSON-serializable value: nil, boolean, number, string or table",
+					},
+				},
+				Example: `spc.result.set({ diskFree = "12 GB" })`,
+			},
+			"get": {
+				Description: "get the current command result",
+				Returns: []luaApi.ReturnDoc{
+					{
+						Type:        luaApi.TypeAny,
+						Description: "value passed to spc.result.set or nil",
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,196 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	luaJson "smart-pc-agent/internal/lib/lua-json"
+	appStorage "smart-pc-agent/internal/storage"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	maxKeyLength = 255
+	maxValueSize = 64 * 1024
+)
+
+type ScriptStorage interface {
+	GetScriptValue(ctx context.Context, commandID string, key string) (string, error)
+	SetScriptValue(ctx context.Context, commandID string, key string, value string) error
+	DeleteScriptValue(ctx context.Context, commandID string, key string) error
+	GetScriptKeys(ctx context.Context, commandID string) ([]string, error)
+}
+
+type Module struct {
+	storage ScriptStorage
+}
+
+func New(storage ScriptStorage) *Module {
+	return &Module{storage: storage}
+}
+
+func (m *Module) Register(l *lua.LState, table *lua.LTable) {
+	l.SetField(table, "get", m.get(l))
+	l.SetField(table, "set", m.set(l))
+	l.SetField(table, "delete", m.delete(l))
+	l.SetField(table, "list", m.list(l))
+}
+
+func (m *Module) get(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		ctx, commandID := mustExecution(l, "storage.get")
+		key := checkKey(l)
+		fallback := l.Get(2)
+
+		raw, err := m.storage.GetScriptValue(ctx, commandID, key)
+		if errors.Is(err, appStorage.ErrNotFound) {
+			l.Push(fallback)
+			return 1
+		}
+		if err != nil {
+			l.RaiseError("storage.get: %s", err.Error())
+			return 0
+		}
+
+		value, err := luaJson.Unmarshal(l, []byte(raw))
+		if err != nil {
+			l.RaiseError("storage.get: failed to decode value of %q: %s", key, err.Error())
+			return 0
+		}
+
+		l.Push(value)
+		return 1
+	})
+}
+
+func (m *Module) set(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		ctx, commandID := mustExecution(l, "storage.set")
+		key := checkKey(l)
+		value := l.Get(2)
+
+		if value == lua.LNil {
+			if err := m.storage.DeleteScriptValue(ctx, commandID, key); err != nil {
+				l.RaiseError("storage.set: %s", err.Error())
+			}
+			return 0
+		}
+
+		data, err := luaJson.Marshal(value)
+		if err != nil {
+			l.RaiseError("storage.set: value of %q is not serializable: %s", key, err.Error())
+			return 0
+		}
+		if len(data) > maxValueSize {
+			l.RaiseError("storage.set: value of %q exceeds %d bytes", key, maxValueSize)
+			return 0
+		}
+
+		if err := m.storage.SetScriptValue(ctx, commandID, key, string(data)); err != nil {
+			l.RaiseError("storage.set: %s", err.Error())
+		}
+		return 0
+	})
+}
+
+func (m *Module) delete(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		ctx, commandID := mustExecution(l, "storage.delete")
+		key := checkKey(l)
+
+		if err := m.storage.DeleteScriptValue(ctx, commandID, key); err != nil {
+			l.RaiseError("storage.delete: %s", err.Error())
+		}
+		return 0
+	})
+}
+
+func (m *Module) list(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		ctx, commandID := mustExecution(l, "storage.list")
+
+		keys, err := m.storage.GetScriptKeys(ctx, commandID)
+		if err != nil {
+			l.RaiseError("storage.list: %s", err.Error())
+			return 0
+		}
+
+		result := l.CreateTable(len(keys), 0)
+		for _, key := range keys {
+			result.Append(lua.LString(key))
+		}
+
+		l.Push(result)
+		return 1
+	})
+}
+
+func mustExecution(l *lua.LState, name string) (context.Context, string) {
+	commandID, ok := luaApi.CommandID(l)
+	if !ok {
+		l.RaiseError("%s: storage is available only when running a saved command", name)
+	}
+	return l.Context(), commandID
+}
+
+func checkKey(l *lua.LState) string {
+	key := l.CheckString(1)
+	if key == "" || len(key) > maxKeyLength {
+		l.ArgError(1, "key must be from 1 to 255 characters long")
+	}
+	return key
+}
+
+func (m *Module) Doc() luaApi.ModuleDoc {
+	keyParam := luaApi.ParamDoc{
+		Name:        "key",
+		Type:        luaApi.TypeString,
+		Description: "key, up to 255 characters",
+	}
+
+	return luaApi.ModuleDoc{
+		Description: "persistent key-value storage of the command, kept between runs and reboots; " +
+			"values are stored as JSON",
+		Functions: map[string]luaApi.FunctionDoc{
+			"get": {
+				Description: "get a stored value",
+				Params: []luaApi.ParamDoc{
+					keyParam,
+					{
+						Name:        "default",
+						Type:        luaApi.TypeAny,
+						Description: "value returned when the key is not set",
+						Optional:    true,
+					},
+				},
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeAny, Description: "stored value or default"},
+				},
+				Example: `local profile = spc.storage.get("profile", "speakers")
+spc.storage.set("profile", profile == "speakers" and "headphones" or "speakers")`,
+			},
+			"set": {
+				Description: "store a value, nil deletes the key",
+				Params: []luaApi.ParamDoc{
+					keyParam,
+					{
+						Name:        "value",
+						Type:        luaApi.TypeAny,
+						Description: "JSON-serializable value up to 64 KiB",
+					},
+				},
+			},
+			"delete": {
+				Description: "delete a key",
+				Params:      []luaApi.ParamDoc{keyParam},
+			},
+			"list": {
+				Description: "list stored keys",
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeTable, Description: "sorted array of keys"},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,328 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"smart-pc-agent/internal/lib/cross-platform/battery"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/shirou/gopsutil/v4/host"
+	"github.com/shirou/gopsutil/v4/load"
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/shirou/gopsutil/v4/net"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const cpuSampleInterval = 100 * time.Millisecond
+
+type Module struct{}
+
+func New() *Module {
+	return &Module{}
+}
+
+func (m *Module) Register(l *lua.LState, table *lua.LTable) {
+	l.SetField(table, "cpu", m.cpu(l))
+	l.SetField(table, "memory", m.memory(l))
+	l.SetField(table, "disks", m.disks(l))
+	l.SetField(table, "uptime", m.uptime(l))
+	l.SetField(table, "hostname", m.hostname(l))
+	l.SetField(table, "os", m.os(l))
+	l.SetField(table, "load", m.load(l))
+	l.SetField(table, "network", m.network(l))
+	l.SetField(table, "battery", m.battery(l))
+}
+
+func (m *Module) cpu(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		percent, err := cpu.PercentWithContext(luaContext(l), cpuSampleInterval, false)
+		if err != nil || len(percent) == 0 {
+			l.RaiseError("system.cpu: %s", errorText(err))
+			return 0
+		}
+
+		l.Push(lua.LNumber(percent[0]))
+		return 1
+	})
+}
+
+func (m *Module) memory(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		vm, err := mem.VirtualMemoryWithContext(luaContext(l))
+		if err != nil {
+			l.RaiseError("system.memory: %s", err.Error())
+			return 0
+		}
+
+		result := l.NewTable()
+		l.SetField(result, "total", lua.LNumber(vm.Total))
+		l.SetField(result, "available", lua.LNumber(vm.Available))
+		l.SetField(result, "used", lua.LNumber(vm.Used))
+		l.SetField(result, "usedPercent", lua.LNumber(vm.UsedPercent))
+
+		l.Push(result)
+		return 1
+	})
+}
+
+func (m *Module) disks(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		ctx := luaContext(l)
+
+		partitions, err := disk.PartitionsWithContext(ctx, false)
+		if err != nil {
+			l.RaiseError("system.disks: %s", err.Error())
+			return 0
+		}
+
+		result := l.NewTable()
+		for _, partition := range partitions {
+			usage, err := disk.UsageWithContext(ctx, partition.Mountpoint)
+			if err != nil {
+				continue
+			}
+
+			item := l.NewTable()
+			l.SetField(item, "mount", lua.LString(partition.Mountpoint))
+			l.SetField(item, "device", lua.LString(partition.Device))
+			l.SetField(item, "fstype", lua.LString(partition.Fstype))
+			l.SetField(item, "total", lua.LNumber(usage.Total))
+			l.SetField(item, "free", lua.LNumber(usage.Free))
+			l.SetField(item, "used", lua.LNumber(usage.Used))
+			l.SetField(item, "usedPercent", lua.LNumber(usage.UsedPercent))
+			result.Append(item)
+		}
+
+		l.Push(result)
+		return 1
+	})
+}
+
+func (m *Module) uptime(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		uptime, err := host.UptimeWithContext(luaContext(l))
+		if err != nil {
+			l.RaiseError("system.uptime: %s", err.Error())
+			return 0
+		}
+
+		l.Push(lua.LNumber(uptime))
+		return 1
+	})
+}
+
+func (m *Module) hostname(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		info, err := host.InfoWithContext(luaContext(l))
+		if err != nil {
+			l.RaiseError("system.hostname: %s", err.Error())
+			return 0
+		}
+
+		l.Push(lua.LString(info.Hostname))
+		return 1
+	})
+}
+
+func (m *Module) os(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		info, err := host.InfoWithContext(luaContext(l))
+		if err != nil {
+			l.RaiseError("system.os: %s", err.Error())
+			return 0
+		}
+
+		result := l.NewTable()
+		l.SetField(result, "os", lua.LString(info.OS))
+		l.SetField(result, "platform", lua.LString(info.Platform))
+		l.SetField(result, "platformFamily", lua.LString(info.PlatformFamily))
+		l.SetField(result, "platformVersion", lua.LString(info.PlatformVersion))
+		l.SetField(result, "kernelVersion", lua.LString(info.KernelVersion))
+		l.SetField(result, "arch", lua.LString(info.KernelArch))
+
+		l.Push(result)
+		return 1
+	})
+}
+
+func (m *Module) load(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		avg, err := load.AvgWithContext(luaContext(l))
+		if err != nil {
+			// например, на Windows средней загрузки нет
+			l.Push(lua.LNil)
+			return 1
+		}
+
+		result := l.NewTable()
+		l.SetField(result, "load1", lua.LNumber(avg.Load1))
+		l.SetField(result, "load5", lua.LNumber(avg.Load5))
+		l.SetField(result, "load15", lua.LNumber(avg.Load15))
+
+		l.Push(result)
+		return 1
+	})
+}
+
+func (m *Module) network(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		interfaces, err := net.InterfacesWithContext(luaContext(l))
+		if err != nil {
+			l.RaiseError("system.network: %s", err.Error())
+			return 0
+		}
+
+		result := l.NewTable()
+		for _, iface := range interfaces {
+			addresses := l.NewTable()
+			for _, addr := range iface.Addrs {
+				addresses.Append(lua.LString(addr.Addr))
+			}
+
+			flags := l.NewTable()
+			up := false
+			for _, flag := range iface.Flags {
+				flags.Append(lua.LString(flag))
+				if flag == "up" {
+					up = true
+				}
+			}
+
+			item := l.NewTable()
+			l.SetField(item, "name", lua.LString(iface.Name))
+			l.SetField(item, "mac", lua.LString(iface.HardwareAddr))
+			l.SetField(item, "mtu", lua.LNumber(iface.MTU))
+			l.SetField(item, "up", lua.LBool(up))
+			l.SetField(item, "flags", flags)
+			l.SetField(item, "addresses", addresses)
+			result.Append(item)
+		}
+
+		l.Push(result)
+		return 1
+	})
+}
+
+func (m *Module) battery(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		status, err := battery.Get()
+		if errors.Is(err, battery.ErrNotPresent) {
+			l.Push(lua.LNil)
+			return 1
+		}
+		if err != nil {
+			l.RaiseError("system.battery: %s", err.Error())
+			return 0
+		}
+
+		result := l.NewTable()
+		l.SetField(result, "percent", lua.LNumber(status.Percent))
+		l.SetField(result, "charging", lua.LBool(status.Charging))
+
+		l.Push(result)
+		return 1
+	})
+}
+
+func luaContext(l *lua.LState) context.Context {
+	if ctx := l.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return "no data"
+	}
+	return err.Error()
+}
+
+func (m *Module) Doc() luaApi.ModuleDoc {
+	return luaApi.ModuleDoc{
+		Description: "host telemetry",
+		Functions: map[string]luaApi.FunctionDoc{
+			"cpu": {
+				Description: "get total CPU usage, measured over 100 ms",
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeNumber, Description: "usage percent from 0 to 100"},
+				},
+			},
+			"memory": {
+				Description: "get virtual memory usage",
+				Returns: []luaApi.ReturnDoc{
+					{
+						Type:        luaApi.TypeTable,
+						Description: "table with fields total, available, used (bytes) and usedPercent",
+					},
+				},
+			},
+			"disks": {
+				Description: "get usage of mounted disks",
+				Returns: []luaApi.ReturnDoc{
+					{
+						Type: luaApi.TypeTable,
+						Description: "array of tables with fields mount, device, fstype, " +
+							"total, free, used (bytes) and usedPercent",
+					},
+				},
+				Example: `for _, d in ipairs(spc.system.disks()) do
+  if d.mount == "/" and d.free > 10 * 1024 ^ 3 then spc.log.info("enough space") end
+end`,
+			},
+			"uptime": {
+				Description: "get time since boot",
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeNumber, Description: "uptime in seconds"},
+				},
+			},
+			"hostname": {
+				Description: "get the host name",
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeString, Description: "host name"},
+				},
+			},
+			"os": {
+				Description: "get operating system information",
+				Returns: []luaApi.ReturnDoc{
+					{
+						Type: luaApi.TypeTable,
+						Description: "table with fields os, platform, platformFamily, " +
+							"platformVersion, kernelVersion and arch",
+					},
+				},
+			},
+			"load": {
+				Description: "get load average",
+				Returns: []luaApi.ReturnDoc{
+					{
+						Type:        luaApi.TypeTable,
+						Description: "table with fields load1, load5 and load15, or nil if not supported",
+					},
+				},
+			},
+			"network": {
+				Description: "get network interfaces",
+				Returns: []luaApi.ReturnDoc{
+					{
+						Type: luaApi.TypeTable,
+						Description: "array of tables with fields name, mac, mtu, up, " +
+							"flags (array) and addresses (array of CIDR strings)",
+					},
+				},
+			},
+			"battery": {
+				Description: "get battery status",
+				Returns: []luaApi.ReturnDoc{
+					{
+						Type:        luaApi.TypeTable,
+						Description: "table with fields percent and charging, or nil if there is no battery",
+					},
+				},
+			},
+		},
+	}
+}
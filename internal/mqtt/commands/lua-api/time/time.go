@@ -0,0 +1,243 @@
+package time
+
+import (
+	"context"
+	"math"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+var layouts = map[string]string{
+	"RFC3339":  time.RFC3339,
+	"RFC1123":  time.RFC1123,
+	"DateTime": time.DateTime,
+	"DateOnly": time.DateOnly,
+	"TimeOnly": time.TimeOnly,
+	"Kitchen":  time.Kitchen,
+}
+
+// maxSleepSeconds — наибольшая длительность, которая помещается в time.Duration
+const maxSleepSeconds = float64(math.MaxInt64) / float64(time.Second)
+
+type Module struct {
+	start time.Time
+}
+
+func New() *Module {
+	return &Module{start: time.Now()}
+}
+
+func (m *Module) Register(l *lua.LState, table *lua.LTable) {
+	l.SetField(table, "now", m.now(l))
+	l.SetField(table, "sleep", m.sleep(l))
+	l.SetField(table, "format", m.format(l))
+	l.SetField(table, "parse", m.parse(l))
+	l.SetField(table, "monotonic", m.monotonic(l))
+	l.SetField(table, "since", m.since(l))
+
+	for name, layout := range layouts {
+		l.SetField(table, name, lua.LString(layout))
+	}
+}
+
+func (m *Module) now(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		l.Push(toTimestamp(time.Now()))
+		return 1
+	})
+}
+
+func (m *Module) sleep(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		seconds := float64(l.CheckNumber(1))
+		if seconds < 0 || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+			l.ArgError(1, "duration must be a non-negative number of seconds")
+			return 0
+		}
+		if seconds >= maxSleepSeconds {
+			l.ArgError(1, "duration is too long")
+			return 0
+		}
+
+		ctx := l.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		wait := time.Duration(seconds * float64(time.Second))
+		// если ожидание не успеет закончиться до дедлайна выполнения,
+		// ждать имеет смысл только сам дедлайн
+		if deadline, ok := ctx.Deadline(); ok && wait >= time.Until(deadline) {
+			<-ctx.Done()
+			l.RaiseError("time.sleep: %s", ctx.Err().Error())
+			return 0
+		}
+
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			l.RaiseError("time.sleep: %s", ctx.Err().Error())
+		case <-timer.C:
+		}
+
+		return 0
+	})
+}
+
+func (m *Module) format(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		layout := l.CheckString(1)
+		t := time.Now()
+		if l.GetTop() >= 2 && l.Get(2) != lua.LNil {
+			t = fromTimestamp(l.CheckNumber(2))
+		}
+		if l.OptBool(3, false) {
+			t = t.UTC()
+		}
+
+		l.Push(lua.LString(t.Format(layout)))
+		return 1
+	})
+}
+
+func (m *Module) parse(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		layout := l.CheckString(1)
+		value := l.CheckString(2)
+
+		t, err := time.ParseInLocation(layout, value, time.Local)
+		if err != nil {
+			l.RaiseError("time.parse: %s", err.Error())
+			return 0
+		}
+
+		l.Push(toTimestamp(t))
+		return 1
+	})
+}
+
+func (m *Module) monotonic(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		l.Push(lua.LNumber(time.Since(m.start).Seconds()))
+		return 1
+	})
+}
+
+func (m *Module) since(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		started := float64(l.CheckNumber(1))
+
+		l.Push(lua.LNumber(time.Since(m.start).Seconds() - started))
+		return 1
+	})
+}
+
+func toTimestamp(t time.Time) lua.LNumber {
+	return lua.LNumber(float64(t.UnixNano()) / float64(time.Second))
+}
+
+func fromTimestamp(timestamp lua.LNumber) time.Time {
+	seconds, fraction := math.Modf(float64(timestamp))
+	return time.Unix(int64(seconds), int64(fraction*float64(time.Second)))
+}
+
+func (m *Module) Doc() luaApi.ModuleDoc {
+	fields := make(map[string]luaApi.FieldDoc, len(layouts))
+	for name, layout := range layouts {
+		fields[name] = luaApi.FieldDoc{
+			Type:        luaApi.TypeString,
+			Description: "Go layout " + layout,
+		}
+	}
+
+	return luaApi.ModuleDoc{
+		Description: "time, sleeping and formatting; timestamps are unix seconds with a fraction, " +
+			"layouts use Go reference time Mon Jan 2 15:04:05 MST 2006",
+		Functions: map[string]luaApi.FunctionDoc{
+			"now": {
+				Description: "get the current time",
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeNumber, Description: "unix timestamp in seconds"},
+				},
+				Example: `local started = spc.time.now()`,
+			},
+			"sleep": {
+				Description: "pause the script, interrupted when the command times out or is cancelled",
+				Params: []luaApi.ParamDoc{
+					{
+						Name:        "seconds",
+						Type:        luaApi.TypeNumber,
+						Description: "duration in seconds, fractions are allowed",
+					},
+				},
+				Example: `spc.media.playPause()
+spc.time.sleep(2)
+spc.volume.set(20)`,
+			},
+			"format": {
+				Description: "format a timestamp in the local time zone",
+				Params: []luaApi.ParamDoc{
+					{
+						Name:        "layout",
+						Type:        luaApi.TypeString,
+						Description: "Go layout, e.g. spc.time.DateTime or \"02.01.2006 15:04\"",
+					},
+					{
+						Name:        "timestamp",
+						Type:        luaApi.TypeNumber,
+						Description: "unix timestamp, defaults to now",
+						Optional:    true,
+					},
+					{
+						Name:        "utc",
+						Type:        luaApi.TypeBoolean,
+						Description: "format in UTC instead of the local time zone",
+						Optional:    true,
+					},
+				},
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeString, Description: "formatted time"},
+				},
+				Example: `spc.log.info(spc.time.format(spc.time.DateTime))`,
+			},
+			"parse": {
+				Description: "parse a time string, the local time zone is used if the layout has none",
+				Params: []luaApi.ParamDoc{
+					{Name: "layout", Type: luaApi.TypeString, Description: "Go layout"},
+					{Name: "value", Type: luaApi.TypeString, Description: "time string"},
+				},
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeNumber, Description: "unix timestamp in seconds"},
+				},
+				Example: `local t = spc.time.parse(spc.time.DateOnly, "2025-01-31")`,
+			},
+			"monotonic": {
+				Description: "get a monotonic clock reading, not affected by system clock changes",
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeNumber, Description: "seconds since an arbitrary point"},
+				},
+			},
+			"since": {
+				Description: "get seconds elapsed since a spc.time.monotonic reading",
+				Params: []luaApi.ParamDoc{
+					{
+						Name:        "start",
+						Type:        luaApi.TypeNumber,
+						Description: "value returned by spc.time.monotonic",
+					},
+				},
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeNumber, Description: "elapsed seconds"},
+				},
+				Example: `local start = spc.time.monotonic()
+spc.process.run("backup")
+spc.log.info("took " .. spc.time.since(start) .. " s")`,
+			},
+		},
+		Fields: fields,
+	}
+}
@@ -0,0 +1,148 @@
+package volume
+
+import (
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+
+	"github.com/itchyny/volume-go"
+	lua "github.com/yuin/gopher-lua"
+)
+
+type Module struct{}
+
+func New() *Module {
+	return &Module{}
+}
+
+func (m *Module) Register(l *lua.LState, table *lua.LTable) {
+	l.SetField(table, "get", m.get(l))
+	l.SetField(table, "set", m.set(l))
+	l.SetField(table, "isMuted", m.isMuted(l))
+	l.SetField(table, "mute", m.mute(l))
+	l.SetField(table, "unmute", m.unmute(l))
+	l.SetField(table, "toggleMute", m.toggleMute(l))
+}
+
+func (m *Module) get(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		current, err := volume.GetVolume()
+		if err != nil {
+			l.RaiseError("volume.get: %s", err.Error())
+			return 0
+		}
+
+		l.Push(lua.LNumber(current))
+		return 1
+	})
+}
+
+func (m *Module) set(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		level := l.CheckInt(1)
+		if level < 0 || level > 100 {
+			l.ArgError(1, "volume must be between 0 and 100")
+			return 0
+		}
+
+		if err := volume.SetVolume(level); err != nil {
+			l.RaiseError("volume.set: %s", err.Error())
+		}
+		return 0
+	})
+}
+
+func (m *Module) isMuted(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		muted, err := volume.GetMuted()
+		if err != nil {
+			l.RaiseError("volume.isMuted: %s", err.Error())
+			return 0
+		}
+
+		l.Push(lua.LBool(muted))
+		return 1
+	})
+}
+
+func (m *Module) mute(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		if err := volume.Mute(); err != nil {
+			l.RaiseError("volume.mute: %s", err.Error())
+		}
+		return 0
+	})
+}
+
+func (m *Module) unmute(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		if err := volume.Unmute(); err != nil {
+			l.RaiseError("volume.unmute: %s", err.Error())
+		}
+		return 0
+	})
+}
+
+func (m *Module) toggleMute(l *lua.LState) lua.LValue {
+	return l.NewFunction(func(l *lua.LState) int {
+		muted, err := volume.GetMuted()
+		if err != nil {
+			l.RaiseError("volume.toggleMute: %s", err.Error())
+			return 0
+		}
+
+		toggle := volume.Mute
+		if muted {
+			toggle = volume.Unmute
+		}
+		if err := toggle(); err != nil {
+			l.RaiseError("volume.toggleMute: %s", err.Error())
+			return 0
+		}
+
+		l.Push(lua.LBool(!muted))
+		return 1
+	})
+}
+
+func (m *Module) Doc() luaApi.ModuleDoc {
+	return luaApi.ModuleDoc{
+		Description: "system volume control",
+		Functions: map[string]luaApi.FunctionDoc{
+			"get": {
+				Description: "get the current volume",
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeNumber, Description: "volume from 0 to 100"},
+				},
+			},
+			"set": {
+				Description: "set the volume",
+				Params: []luaApi.ParamDoc{
+					{
+						Name:        "volume",
+						Type:        luaApi.TypeNumber,
+						Description: "volume from 0 to 100",
+					},
+				},
+				Example: `spc.media.playPause()
+spc.volume.set(20)`,
+			},
+			"isMuted": {
+				Description: "check whether the sound is muted",
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeBoolean, Description: "true if muted"},
+				},
+			},
+			"mute": {
+				Description: "mute the sound",
+			},
+			"unmute": {
+				Description: "unmute the sound",
+			},
+			"toggleMute": {
+				Description: "mute the sound if it is not muted and unmute otherwise",
+				Returns: []luaApi.ReturnDoc{
+					{Type: luaApi.TypeBoolean, Description: "true if the sound is muted now"},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,114 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"smart-pc-agent/internal/config"
+	"smart-pc-agent/internal/domain/models"
+	"smart-pc-agent/internal/mqtt/commands/executions"
+	"time"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/commands"
+	"github.com/MaxRomanov007/smart-pc-go-lib/domain/models/message"
+	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
+)
+
+type ExecutionSaver interface {
+	StartExecution(
+		ctx context.Context,
+		execution models.CommandExecution,
+	) (models.CommandExecution, error)
+	FinishExecution(
+		ctx context.Context,
+		id int64,
+		status string,
+		errorMessage string,
+		finishedAt time.Time,
+	) (models.CommandExecution, error)
+	PruneExecutions(
+		ctx context.Context,
+		commandID string,
+		startedBefore time.Time,
+		keep int64,
+	) error
+}
+
+// redactedParameters сохраняется вместо параметров, которые нельзя хранить
+var redactedParameters = json.RawMessage(`"[redacted]"`)
+
+// New записывает каждое выполнение и удаляет записи, которые вышли за
+// ограничения cfg. Параметры команд из redacted не сохраняются, например
+// текст, который кладётся в буфер обмена.
+func New(
+	log *slog.Logger,
+	cfg config.History,
+	saver ExecutionSaver,
+	redacted ...string,
+) func(next commands.CommandFunc) commands.CommandFunc {
+	return func(next commands.CommandFunc) commands.CommandFunc {
+		return func(ctx context.Context, msg *message.Message) error {
+			const op = "commands.middlewares.history"
+
+			log := log.With(
+				sl.Op(op),
+				sl.MsgID(msg.Publish),
+				slog.String("command", msg.Data.Command),
+			)
+
+			parameters := redactedParameters
+			if !slices.Contains(redacted, msg.Data.Command) {
+				var err error
+				parameters, err = json.Marshal(msg.Data.Parameter)
+				if err != nil {
+					log.Warn("failed to marshal message parameter", sl.Err(err))
+				}
+			}
+
+			started, err := saver.StartExecution(ctx, models.CommandExecution{
+				CommandID:  msg.Data.Command,
+				MessageID:  executions.MessageID(msg),
+				Parameters: parameters,
+				StartedAt:  time.Now().UTC(),
+			})
+			if err != nil {
+				log.Error("failed to save execution start", sl.Err(err))
+				return next(ctx, msg)
+			}
+
+			handlerErr := next(ctx, msg)
+
+			status, errorMessage := models.ExecutionStatusSuccess, ""
+			if handlerErr != nil {
+				status, errorMessage = models.ExecutionStatusError, handlerErr.Error()
+			}
+
+			// выполнение могло быть отменено вместе с ctx, запись всё равно нужна
+			if _, err := saver.FinishExecution(
+				context.WithoutCancel(ctx),
+				started.ID,
+				status,
+				errorMessage,
+				time.Now().UTC(),
+			); err != nil {
+				log.Error("failed to save execution finish", sl.Err(err))
+			}
+
+			var startedBefore time.Time
+			if cfg.MaxAge > 0 {
+				startedBefore = time.Now().UTC().Add(-cfg.MaxAge)
+			}
+			if err := saver.PruneExecutions(
+				context.WithoutCancel(ctx),
+				msg.Data.Command,
+				startedBefore,
+				cfg.MaxPerCommand,
+			); err != nil {
+				log.Error("failed to prune executions", sl.Err(err))
+			}
+
+			return handlerErr
+		}
+	}
+}
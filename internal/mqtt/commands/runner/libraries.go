@@ -0,0 +1,103 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"smart-pc-agent/internal/domain/models"
+	"smart-pc-agent/internal/storage"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+type LibraryGetter interface {
+	GetLibrary(ctx context.Context, name string) (models.Library, error)
+}
+
+// libraryLoader загружает библиотеки из хранилища для require и следит
+// за цепочкой загружаемых библиотек, чтобы сообщать о циклах
+type libraryLoader struct {
+	ctx     context.Context
+	getter  LibraryGetter
+	loading []string
+}
+
+// installLibraries заменяет поиск файлов в package.loaders загрузкой
+// библиотек из хранилища и оборачивает require проверкой циклов
+func installLibraries(ctx context.Context, l *lua.LState, getter LibraryGetter) {
+	loader := &libraryLoader{ctx: ctx, getter: getter}
+
+	pkg, ok := l.GetGlobal(lua.LoadLibName).(*lua.LTable)
+	if !ok {
+		return
+	}
+
+	// первым остаётся загрузчик package.preload, остальные заменяются
+	loaders := l.CreateTable(2, 0)
+	if preload, ok := l.GetField(pkg, "loaders").(*lua.LTable); ok {
+		loaders.Append(preload.RawGetInt(1))
+	}
+	loaders.Append(l.NewFunction(loader.load))
+	l.SetField(pkg, "loaders", loaders)
+	l.SetField(l.Get(lua.RegistryIndex), "_LOADERS", loaders)
+
+	if require, ok := l.GetGlobal("require").(*lua.LFunction); ok {
+		l.SetGlobal("require", l.NewFunction(loader.require(require)))
+	}
+}
+
+func (ll *libraryLoader) require(original *lua.LFunction) lua.LGFunction {
+	return func(l *lua.LState) int {
+		name := l.CheckString(1)
+		if slices.Contains(ll.loading, name) {
+			chain := append(slices.Clone(ll.loading), name)
+			l.RaiseError("require: cycle detected: %s", strings.Join(chain, " -> "))
+			return 0
+		}
+
+		l.Push(original)
+		l.Push(lua.LString(name))
+		l.Call(1, 1)
+		return 1
+	}
+}
+
+func (ll *libraryLoader) load(l *lua.LState) int {
+	name := l.CheckString(1)
+
+	library, err := ll.getter.GetLibrary(ll.ctx, name)
+	if errors.Is(err, storage.ErrNotFound) {
+		l.Push(lua.LString("\n\tno library '" + name + "'"))
+		return 1
+	}
+	if err != nil {
+		l.RaiseError("require: failed to get library %s: %s", name, err.Error())
+		return 0
+	}
+
+	chunk, err := parse.Parse(strings.NewReader(library.Source), name)
+	if err != nil {
+		l.RaiseError("require: syntax error in library %s: %s", name, err.Error())
+		return 0
+	}
+	proto, err := lua.Compile(chunk, name)
+	if err != nil {
+		l.RaiseError("require: failed to compile library %s: %s", name, err.Error())
+		return 0
+	}
+	body := l.NewFunctionFromProto(proto)
+
+	l.Push(l.NewFunction(func(l *lua.LState) int {
+		// при ошибке в библиотеке Call паникует, defer снимает её со стека
+		ll.loading = append(ll.loading, name)
+		defer func() { ll.loading = ll.loading[:len(ll.loading)-1] }()
+
+		l.Push(body)
+		l.Push(lua.LString(name))
+		l.Call(1, 1)
+		return 1
+	}))
+	return 1
+}
@@ -0,0 +1,135 @@
+package runner
+
+import (
+	"slices"
+	"smart-pc-agent/internal/domain/models"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+)
+
+// типы параметров команд, совпадают с command_params.type
+const (
+	TypeBool     = 1
+	TypeNumber   = 2
+	TypeString   = 3
+	TypeInteger  = 4
+	TypeEnum     = 5
+	TypeList     = 6
+	TypeObject   = 7
+	TypeDuration = 8
+)
+
+// ограничения, которые может поддерживать тип параметра
+const (
+	ConstraintMin      = "min"
+	ConstraintMax      = "max"
+	ConstraintEnum     = "enum"
+	ConstraintItemType = "itemType"
+)
+
+var paramTypes = []luaApi.ParamTypeDoc{
+	{
+		ID:          TypeBool,
+		Name:        "bool",
+		LuaType:     luaApi.TypeBoolean,
+		Description: "true or false, accepts the values of strconv.ParseBool",
+	},
+	{
+		ID:          TypeNumber,
+		Name:        "number",
+		LuaType:     luaApi.TypeNumber,
+		Description: "finite number, min and max limit the value",
+		Constraints: []string{ConstraintMin, ConstraintMax, ConstraintEnum},
+	},
+	{
+		ID:          TypeString,
+		Name:        "string",
+		LuaType:     luaApi.TypeString,
+		Description: "text, min and max limit the length in characters",
+		Constraints: []string{ConstraintMin, ConstraintMax, ConstraintEnum},
+	},
+	{
+		ID:          TypeInteger,
+		Name:        "integer",
+		LuaType:     luaApi.TypeInteger,
+		Description: "whole number, min and max limit the value",
+		Constraints: []string{ConstraintMin, ConstraintMax, ConstraintEnum},
+	},
+	{
+		ID:          TypeEnum,
+		Name:        "enum",
+		LuaType:     luaApi.TypeString,
+		Description: "one of the allowed values from enum, which is required",
+		Constraints: []string{ConstraintEnum},
+	},
+	{
+		ID:      TypeList,
+		Name:    "list",
+		LuaType: luaApi.TypeTable,
+		Description: "JSON array of values of itemType (string by default), " +
+			"min and max limit the number of items, enum limits every item",
+		Constraints: []string{ConstraintMin, ConstraintMax, ConstraintEnum, ConstraintItemType},
+		ItemTypes:   []int16{TypeNumber, TypeString, TypeInteger},
+	},
+	{
+		ID:          TypeObject,
+		Name:        "object",
+		LuaType:     luaApi.TypeTable,
+		Description: "JSON object, passed to the script as a table",
+	},
+	{
+		ID:      TypeDuration,
+		Name:    "duration",
+		LuaType: luaApi.TypeNumber,
+		Description: "duration like 1m30s or a number of seconds, passed to the script " +
+			"in seconds, min and max are in seconds too",
+		Constraints: []string{ConstraintMin, ConstraintMax},
+	},
+}
+
+// ParamTypes описывает поддерживаемые типы параметров для /api/schema
+func ParamTypes() []luaApi.ParamTypeDoc {
+	return slices.Clone(paramTypes)
+}
+
+// ParamType возвращает тип параметра команды в терминах схемы API
+func ParamType(param models.CommandParameter) string {
+	switch param.Type {
+	case TypeBool:
+		return luaApi.TypeBoolean
+	case TypeNumber, TypeDuration:
+		return luaApi.TypeNumber
+	case TypeString, TypeEnum:
+		return luaApi.TypeString
+	case TypeInteger:
+		return luaApi.TypeInteger
+	case TypeList:
+		return ParamType(models.CommandParameter{Type: listItemType(param)}) + "[]"
+	case TypeObject:
+		return luaApi.TypeTable
+	default:
+		return luaApi.TypeAny
+	}
+}
+
+func paramTypeDoc(paramType int16) (luaApi.ParamTypeDoc, bool) {
+	index := slices.IndexFunc(paramTypes, func(doc luaApi.ParamTypeDoc) bool {
+		return doc.ID == paramType
+	})
+	if index < 0 {
+		return luaApi.ParamTypeDoc{}, false
+	}
+	return paramTypes[index], true
+}
+
+func supports(paramType int16, constraint string) bool {
+	doc, ok := paramTypeDoc(paramType)
+	return ok && slices.Contains(doc.Constraints, constraint)
+}
+
+// listItemType возвращает тип элементов списка, по умолчанию строки
+func listItemType(param models.CommandParameter) int16 {
+	if param.ItemType == 0 {
+		return TypeString
+	}
+	return param.ItemType
+}
@@ -0,0 +1,345 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"smart-pc-agent/internal/domain/models"
+	luaJson "smart-pc-agent/internal/lib/lua-json"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+var (
+	ErrRequired    = errors.New("is required")
+	ErrNotBool     = errors.New("must be a boolean")
+	ErrNotNumber   = errors.New("must be a number")
+	ErrNotInteger  = errors.New("must be an integer")
+	ErrNotDuration = errors.New("must be a non-negative duration")
+	ErrNotList     = errors.New("must be a JSON array")
+	ErrNotObject   = errors.New("must be a JSON object")
+	ErrNotAllowed  = errors.New("is not one of the allowed values")
+	ErrTooSmall    = errors.New("is less than the minimum")
+	ErrTooLarge    = errors.New("is greater than the maximum")
+)
+
+// ParamError описывает параметр, значение которого не прошло проверку
+type ParamError struct {
+	Name string
+	Err  error
+	// Detail уточняет ограничение, например "min 0"
+	Detail string
+}
+
+func (e ParamError) Error() string {
+	message := e.Err.Error()
+	if e.Detail != "" {
+		message += " (" + e.Detail + ")"
+	}
+	if e.Name != "" {
+		message = e.Name + " " + message
+	}
+	return message
+}
+
+func (e ParamError) Unwrap() error {
+	return e.Err
+}
+
+// CheckParam проверяет, что ограничения параметра подходят к его типу,
+// согласованы между собой и значение по умолчанию им удовлетворяет
+func CheckParam(param models.CommandParameter) error {
+	doc, ok := paramTypeDoc(param.Type)
+	if !ok {
+		return fmt.Errorf("unknown type %d", param.Type)
+	}
+
+	if (param.Min != nil || param.Max != nil) && !supports(param.Type, ConstraintMin) {
+		return fmt.Errorf("min and max are not supported for %s parameters", doc.Name)
+	}
+	if param.Min != nil && param.Max != nil && *param.Min > *param.Max {
+		return errors.New("min is greater than max")
+	}
+	if param.Min != nil && *param.Min < 0 && param.Type != TypeNumber && param.Type != TypeInteger {
+		return errors.New("min can not be negative")
+	}
+
+	if len(param.Enum) > 0 && !supports(param.Type, ConstraintEnum) {
+		return fmt.Errorf("enum is not supported for %s parameters", doc.Name)
+	}
+	if param.Type == TypeEnum && len(param.Enum) == 0 {
+		return errors.New("enum parameters require allowed values")
+	}
+
+	if param.ItemType != 0 && !supports(param.Type, ConstraintItemType) {
+		return fmt.Errorf("item type is not supported for %s parameters", doc.Name)
+	}
+	if param.ItemType != 0 && !slices.Contains(doc.ItemTypes, param.ItemType) {
+		return fmt.Errorf("item type %d is not supported for %s parameters", param.ItemType, doc.Name)
+	}
+
+	for _, value := range param.Enum {
+		itemType := param.Type
+		if param.Type == TypeList {
+			itemType = listItemType(param)
+		}
+		if _, _, err := parseValue(itemType, value); err != nil {
+			return fmt.Errorf("enum value %q %w", value, err)
+		}
+	}
+	if param.Default != nil {
+		if _, err := parseParam(param, *param.Default); err != nil {
+			return fmt.Errorf("default value %w", err)
+		}
+	}
+	return nil
+}
+
+// parseParams переводит строковые значения из сообщения в значения,
+// которые получит скрипт. Отсутствующие значения заменяются значениями
+// по умолчанию, необязательные параметры без значения в результат не попадают.
+func parseParams(
+	params []models.CommandParameter,
+	values map[string]string,
+) (map[string]any, []ParamError) {
+	parsed := make(map[string]any, len(params))
+	var invalid []ParamError
+
+	for _, param := range params {
+		raw, ok := values[param.Name]
+		if !ok && param.Default != nil {
+			raw, ok = *param.Default, true
+		}
+		if !ok {
+			if param.Required {
+				invalid = append(invalid, ParamError{Name: param.Name, Err: ErrRequired})
+			}
+			continue
+		}
+
+		value, err := parseParam(param, raw)
+		if err != nil {
+			paramErr, isParamErr := errors.AsType[ParamError](err)
+			if !isParamErr {
+				paramErr = ParamError{Err: err}
+			}
+			// у ошибок элементов списка в Name записан номер элемента
+			paramErr.Name = strings.TrimSpace(param.Name + " " + paramErr.Name)
+			invalid = append(invalid, paramErr)
+			continue
+		}
+		parsed[param.Name] = value
+	}
+
+	return parsed, invalid
+}
+
+// parseParam разбирает одно значение и проверяет ограничения
+func parseParam(param models.CommandParameter, raw string) (any, error) {
+	switch param.Type {
+	case TypeList:
+		return parseList(param, raw)
+	case TypeObject:
+		return parseObject(raw)
+	}
+
+	value, size, err := parseValue(param.Type, raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEnum(param, param.Type, value); err != nil {
+		return nil, err
+	}
+	if err := checkRange(param, size); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// parseValue разбирает скалярное значение без проверки ограничений.
+// size — величина, которую ограничивают min и max.
+func parseValue(paramType int16, raw string) (value any, size float64, err error) {
+	switch paramType {
+	case TypeBool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, 0, ErrNotBool
+		}
+		return value, 0, nil
+
+	case TypeNumber:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, 0, ErrNotNumber
+		}
+		return value, value, nil
+
+	case TypeInteger:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) || value != math.Trunc(value) {
+			return nil, 0, ErrNotInteger
+		}
+		return value, value, nil
+
+	case TypeString, TypeEnum:
+		return raw, float64(utf8.RuneCountInString(raw)), nil
+
+	case TypeDuration:
+		seconds, err := parseDuration(raw)
+		if err != nil {
+			return nil, 0, err
+		}
+		return seconds, seconds, nil
+
+	default:
+		return nil, 0, fmt.Errorf("has unknown type %d", paramType)
+	}
+}
+
+// parseDuration принимает строку time.ParseDuration или число секунд
+func parseDuration(raw string) (float64, error) {
+	seconds, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return 0, ErrNotDuration
+		}
+		seconds = duration.Seconds()
+	}
+	if seconds < 0 || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return 0, ErrNotDuration
+	}
+	return seconds, nil
+}
+
+// parseList разбирает JSON-массив; элементы могут быть строками или числами
+// и приводятся к типу элементов списка
+func parseList(param models.CommandParameter, raw string) (any, error) {
+	var items []any
+	if err := decodeJSON(raw, &items); err != nil || items == nil {
+		return nil, ErrNotList
+	}
+
+	itemType := listItemType(param)
+	values := make([]any, len(items))
+	for i, item := range items {
+		var itemRaw string
+		switch item := item.(type) {
+		case string:
+			itemRaw = item
+		case json.Number:
+			itemRaw = item.String()
+		default:
+			return nil, ParamError{Name: itemName(i), Err: errors.New("must be a string or a number")}
+		}
+
+		value, _, err := parseValue(itemType, itemRaw)
+		if err != nil {
+			return nil, ParamError{Name: itemName(i), Err: err}
+		}
+		if err := checkEnum(param, itemType, value); err != nil {
+			paramErr, _ := errors.AsType[ParamError](err)
+			paramErr.Name = itemName(i)
+			return nil, paramErr
+		}
+		values[i] = value
+	}
+
+	if err := checkRange(param, float64(len(values))); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func itemName(index int) string {
+	return "item " + strconv.Itoa(index+1)
+}
+
+func parseObject(raw string) (any, error) {
+	var object map[string]any
+	if err := decodeJSON(raw, &object); err != nil || object == nil {
+		return nil, ErrNotObject
+	}
+	return object, nil
+}
+
+// decodeJSON сохраняет числа как json.Number, чтобы не терять точность
+func decodeJSON(raw string, target any) error {
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(target); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("unexpected data after top-level value")
+	}
+	return nil
+}
+
+// checkEnum сравнивает разобранное значение с разобранными допустимыми
+// значениями, поэтому для чисел 5, 5.0 и 5e0 совпадают
+func checkEnum(param models.CommandParameter, valueType int16, value any) error {
+	if len(param.Enum) == 0 {
+		return nil
+	}
+
+	for _, raw := range param.Enum {
+		allowed, _, err := parseValue(valueType, raw)
+		if err == nil && allowed == value {
+			return nil
+		}
+	}
+	return ParamError{
+		Err:    ErrNotAllowed,
+		Detail: strings.Join(param.Enum, ", "),
+	}
+}
+
+func checkRange(param models.CommandParameter, size float64) error {
+	if param.Min != nil && size < *param.Min {
+		return ParamError{Err: ErrTooSmall, Detail: "min " + formatLimit(param, *param.Min)}
+	}
+	if param.Max != nil && size > *param.Max {
+		return ParamError{Err: ErrTooLarge, Detail: "max " + formatLimit(param, *param.Max)}
+	}
+	return nil
+}
+
+func formatLimit(param models.CommandParameter, limit float64) string {
+	number := strconv.FormatFloat(limit, 'f', -1, 64)
+	switch param.Type {
+	case TypeString, TypeEnum:
+		return "length " + number
+	case TypeList:
+		return number + " items"
+	case TypeDuration:
+		return time.Duration(limit * float64(time.Second)).String()
+	default:
+		return number
+	}
+}
+
+func createParamsTable(l *lua.LState, parsed map[string]any) *lua.LTable {
+	paramsTable := l.CreateTable(0, len(parsed))
+	for name, value := range parsed {
+		l.SetField(paramsTable, name, luaJson.FromGo(l, value))
+	}
+	return paramsTable
+}
+
+func paramsError(invalid []ParamError) *Error {
+	messages := make([]string, len(invalid))
+	for i, paramErr := range invalid {
+		messages[i] = paramErr.Error()
+	}
+	return &Error{
+		Class:   ClassParams,
+		Message: strings.Join(messages, "; "),
+	}
+}
@@ -0,0 +1,225 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"smart-pc-agent/internal/config"
+	"smart-pc-agent/internal/domain/models"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	luaJson "smart-pc-agent/internal/lib/lua-json"
+	"smart-pc-agent/internal/mqtt/commands/executions"
+	"smart-pc-agent/internal/mqtt/commands/lua-api/result"
+	"time"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// классы ошибок выполнения скрипта
+const (
+	ClassTimeout   = "timeout"
+	ClassCancelled = "cancelled"
+	ClassLimit     = "limit"
+	ClassSyntax    = "syntax"
+	ClassFile      = "file"
+	ClassRun       = "run"
+	ClassError     = "error"
+	ClassPanic     = "panic"
+	ClassAPI       = "api"
+	ClassResult    = "result"
+	ClassParams    = "params"
+)
+
+// Error — ошибка скрипта, о которой нужно сообщить пользователю
+type Error struct {
+	Class   string
+	Message string
+}
+
+func (e *Error) Error() string {
+	switch e.Class {
+	case ClassParams:
+		return "invalid parameters: " + e.Message
+	case ClassTimeout, ClassCancelled, ClassError:
+		return e.Class + ": " + e.Message
+	default:
+		return e.Class + " error: " + e.Message
+	}
+}
+
+// Script описывает одно выполнение
+type Script struct {
+	// CommandID пустой, если скрипт не сохранён как команда
+	CommandID    string
+	Source       string
+	Capabilities []string
+	Parameters   []models.CommandParameter
+	// Values — значения параметров в виде строк, как они приходят в сообщении
+	Values map[string]string
+	// Timeout равен 0, если нужно использовать таймаут из конфига
+	Timeout time.Duration
+}
+
+// Result — итог выполнения, заполняется и при ошибке скрипта
+type Result struct {
+	// Value равен nil, если скрипт ничего не вернул
+	Value       json.RawMessage
+	Logs        []luaApi.LogEntry
+	DroppedLogs int
+	Duration    time.Duration
+}
+
+type Runner struct {
+	log           *slog.Logger
+	cfg           config.Scripts
+	libraryGetter LibraryGetter
+	registry      *luaApi.Registry
+}
+
+func New(
+	log *slog.Logger,
+	cfg config.Scripts,
+	libraryGetter LibraryGetter,
+	registry *luaApi.Registry,
+) *Runner {
+	return &Runner{
+		log:           log,
+		cfg:           cfg,
+		libraryGetter: libraryGetter,
+		registry:      registry,
+	}
+}
+
+// Timeout возвращает таймаут, с которым будет выполнен script
+func (r *Runner) Timeout(script Script) time.Duration {
+	if script.Timeout > 0 {
+		return script.Timeout
+	}
+	return r.cfg.Timeout
+}
+
+// Run выполняет скрипт в песочнице с учётом таймаута и лимитов.
+// Ошибки самого скрипта возвращаются как *Error.
+func (r *Runner) Run(ctx context.Context, script Script) (Result, error) {
+	const op = "commands.runner.Run"
+
+	log := r.log.With(sl.Op(op), slog.String("command", script.CommandID))
+
+	params, invalid := parseParams(script.Parameters, script.Values)
+	if len(invalid) > 0 {
+		err := paramsError(invalid)
+		log.Warn("invalid script parameters", sl.Err(err))
+		return Result{}, err
+	}
+
+	timeout := r.Timeout(script)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ctx = luaApi.WithCommandID(ctx, script.CommandID)
+
+	logs := &luaApi.Logs{}
+	ctx = luaApi.WithLogs(ctx, logs)
+
+	limits := luaApi.Limits(r.cfg.Limits)
+	ctx, cancelLimits := luaApi.WithLimits(ctx, limits)
+	defer cancelLimits()
+
+	l, spc := r.newState(ctx, limits, script.Capabilities)
+	defer l.Close()
+	l.SetField(spc, "params", createParamsTable(l, params))
+
+	started := time.Now()
+	top := l.GetTop()
+	err := l.DoString(script.Source)
+
+	var res Result
+	res.Duration = time.Since(started)
+	res.Logs, res.DroppedLogs = logs.Entries()
+
+	if err := classify(ctx, log, l, err, timeout); err != nil {
+		return res, err
+	}
+
+	value := result.Get(l)
+	if l.GetTop() > top && l.Get(top+1) != lua.LNil {
+		value = l.Get(top + 1)
+	}
+	if value == lua.LNil {
+		return res, nil
+	}
+
+	data, err := luaJson.Marshal(value)
+	if err != nil {
+		log.Warn("failed to marshal script result", sl.Err(err))
+		return res, &Error{Class: ClassResult, Message: err.Error()}
+	}
+	res.Value = data
+
+	return res, nil
+}
+
+// newState создаёт lua-состояние в песочнице с библиотеками и таблицей spc
+// для capabilities. Таблица spc возвращается, чтобы дополнить её params.
+func (r *Runner) newState(
+	ctx context.Context,
+	limits luaApi.Limits,
+	capabilities []string,
+) (*lua.LState, *lua.LTable) {
+	l := luaApi.NewState(limits)
+	l.SetContext(ctx)
+	installLibraries(ctx, l, r.libraryGetter)
+
+	spc := r.registry.BuildTable(l, capabilities)
+	l.SetGlobal("spc", spc)
+
+	return l, spc
+}
+
+// classify превращает результат выполнения в *Error, если скрипт упал,
+// был отменён или вышел за ограничения
+func classify(
+	ctx context.Context,
+	log *slog.Logger,
+	l *lua.LState,
+	err error,
+	timeout time.Duration,
+) error {
+	const op = "commands.runner.classify"
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		log.Warn("script execution timed out", slog.Duration("timeout", timeout))
+		return &Error{Class: ClassTimeout, Message: "script execution exceeded " + timeout.String()}
+	}
+	if errors.Is(context.Cause(ctx), executions.ErrCancelled) {
+		log.Info("script execution cancelled")
+		return &Error{Class: ClassCancelled, Message: "script execution cancelled"}
+	}
+	if limitErr := luaApi.LimitError(l, err); limitErr != nil {
+		log.Warn("script exceeded limits", sl.Err(limitErr))
+		return &Error{Class: ClassLimit, Message: limitErr.Error()}
+	}
+	if apiErr, ok := errors.AsType[*lua.ApiError](err); ok {
+		switch apiErr.Type {
+		case lua.ApiErrorSyntax:
+			return &Error{Class: ClassSyntax, Message: apiErr.Error()}
+		case lua.ApiErrorFile:
+			return &Error{Class: ClassFile, Message: apiErr.Error()}
+		case lua.ApiErrorRun:
+			return &Error{Class: ClassRun, Message: apiErr.Error()}
+		case lua.ApiErrorError:
+			return &Error{Class: ClassError, Message: apiErr.Error()}
+		case lua.ApiErrorPanic:
+			return &Error{Class: ClassPanic, Message: apiErr.Error()}
+		default:
+			return &Error{Class: ClassAPI, Message: apiErr.Error()}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute script: %w", op, err)
+	}
+	return nil
+}
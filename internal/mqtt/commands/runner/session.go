@@ -0,0 +1,107 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	luaApi "smart-pc-agent/internal/lib/lua-api"
+	luaJson "smart-pc-agent/internal/lib/lua-json"
+	"time"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Session — долгоживущее lua-состояние для интерактивного выполнения.
+// Глобальные переменные сохраняются между вызовами Eval, локальные — нет,
+// как в стандартном интерпретаторе Lua. Session не потокобезопасна.
+type Session struct {
+	runner *Runner
+	log    *slog.Logger
+	l      *lua.LState
+	limits luaApi.Limits
+}
+
+// EvalResult — итог выполнения одного фрагмента
+type EvalResult struct {
+	// Values — возвращённые значения в JSON, значения, которые нельзя
+	// сериализовать, передаются строкой из tostring
+	Values   []json.RawMessage
+	Duration time.Duration
+}
+
+// NewSession создаёт сессию в той же песочнице, что и обычное выполнение;
+// ctx ограничивает время жизни сессии и используется для загрузки библиотек
+func (r *Runner) NewSession(ctx context.Context, capabilities []string) *Session {
+	const op = "commands.runner.NewSession"
+
+	limits := luaApi.Limits(r.cfg.Limits)
+	l, spc := r.newState(ctx, limits, capabilities)
+	l.SetField(spc, "params", l.NewTable())
+
+	return &Session{
+		runner: r,
+		log:    r.log.With(sl.Op(op)),
+		l:      l,
+		limits: limits,
+	}
+}
+
+// Eval выполняет фрагмент кода с таймаутом и лимитами из конфига. Сначала
+// фрагмент пробуется как выражение, чтобы "1 + 1" вернуло значение.
+// Сообщения spc.log передаются в onLog по мере выполнения.
+func (s *Session) Eval(
+	ctx context.Context,
+	code string,
+	onLog func(luaApi.LogEntry),
+) (EvalResult, error) {
+	timeout := s.runner.cfg.Timeout
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ctx = luaApi.WithLogs(ctx, luaApi.NewStreamingLogs(onLog))
+
+	ctx, cancelLimits := luaApi.WithLimits(ctx, s.limits)
+	defer cancelLimits()
+
+	s.l.SetContext(ctx)
+	defer s.l.RemoveContext()
+
+	top := s.l.GetTop()
+	defer s.l.SetTop(top)
+
+	started := time.Now()
+	fn, err := s.l.LoadString("return " + code)
+	if err != nil {
+		fn, err = s.l.LoadString(code)
+	}
+	if err == nil {
+		s.l.Push(fn)
+		err = s.l.PCall(0, lua.MultRet, nil)
+	}
+
+	var res EvalResult
+	res.Duration = time.Since(started)
+
+	if err := classify(ctx, s.log, s.l, err, timeout); err != nil {
+		return res, err
+	}
+
+	for i := top + 1; i <= s.l.GetTop(); i++ {
+		res.Values = append(res.Values, s.marshal(s.l.Get(i)))
+	}
+
+	return res, nil
+}
+
+func (s *Session) marshal(value lua.LValue) json.RawMessage {
+	if data, err := luaJson.Marshal(value); err == nil {
+		return data
+	}
+	data, _ := json.Marshal(lua.LVAsString(s.l.ToStringMeta(value)))
+	return data
+}
+
+func (s *Session) Close() {
+	s.l.Close()
+}
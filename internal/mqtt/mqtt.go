@@ -6,15 +6,22 @@ import (
 	"log/slog"
 	"net/url"
 	"smart-pc-agent/internal/config"
-	luaApi "smart-pc-agent/internal/lib/lua-api"
 	"smart-pc-agent/internal/lib/random"
+	"smart-pc-agent/internal/mqtt/commands/executions"
+	cancelExecution "smart-pc-agent/internal/mqtt/commands/handlers/cancel-execution"
+	clipboardGet "smart-pc-agent/internal/mqtt/commands/handlers/clipboard-get"
+	clipboardSet "smart-pc-agent/internal/mqtt/commands/handlers/clipboard-set"
 	executeScript "smart-pc-agent/internal/mqtt/commands/handlers/execute-script"
 	"smart-pc-agent/internal/mqtt/commands/handlers/mute"
 	nextTrack "smart-pc-agent/internal/mqtt/commands/handlers/next-track"
 	playPause "smart-pc-agent/internal/mqtt/commands/handlers/play-pause"
 	prevTrack "smart-pc-agent/internal/mqtt/commands/handlers/prev-track"
 	setVolume "smart-pc-agent/internal/mqtt/commands/handlers/set-volume"
+	showNotification "smart-pc-agent/internal/mqtt/commands/handlers/show-notification"
 	"smart-pc-agent/internal/mqtt/commands/handlers/unmute"
+	luaMqtt "smart-pc-agent/internal/mqtt/commands/lua-api/mqtt"
+	"smart-pc-agent/internal/mqtt/commands/middlewares/history"
+	"smart-pc-agent/internal/mqtt/commands/runner"
 
 	"github.com/MaxRomanov007/smart-pc-go-lib/authorization"
 	"github.com/MaxRomanov007/smart-pc-go-lib/commands"
@@ -28,6 +35,11 @@ type MQTT struct {
 	Connection *mqttAuth.Connection
 }
 
+// ScriptPublisher получает соединение для публикации из скриптов через spc.mqtt
+type ScriptPublisher interface {
+	Connect(publisher luaMqtt.Publisher, pcID string)
+}
+
 type PcIDGetter interface {
 	GetPcID(ctx context.Context) (string, error)
 }
@@ -36,11 +48,14 @@ func New(
 	ctx context.Context,
 	log *slog.Logger,
 	mqttCfg config.MQTT,
+	historyCfg config.History,
 	auth *authorization.Auth,
-	registry *luaApi.Registry,
+	scriptPublisher ScriptPublisher,
 	pcIDGetter PcIDGetter,
 	commandGetter executeScript.CommandGetter,
 	commandParamsGetter executeScript.CommandParamsGetter,
+	scriptRunner *runner.Runner,
+	executionSaver history.ExecutionSaver,
 ) (*MQTT, error) {
 	const op = "mqtt.New"
 
@@ -66,14 +81,34 @@ func New(
 
 	startSendState(ctx, localCtx, pcID, log, connection, cancel)
 
+	scriptPublisher.Connect(connection, pcID)
+
+	running := executions.NewRegistry()
+
+	// текст буфера обмена и уведомлений может содержать коды и ссылки
+	withHistory := history.New(log, historyCfg, executionSaver, "clipboard-set", "notify")
+
+	results := newResultPublisher(connection, pcID)
+
 	executor := commands.NewExecutor(connection, router)
-	executor.SetDefault(executeScript.New(log, commandGetter, commandParamsGetter, registry))
-	executor.Set("mute", mute.New(log))
-	executor.Set("unmute", unmute.New(log))
-	executor.Set("set-volume", setVolume.New(log))
-	executor.Set("play-pause", playPause.New(log))
-	executor.Set("next-track", nextTrack.New(log))
-	executor.Set("prev-track", prevTrack.New(log))
+	executor.SetDefault(withHistory(executeScript.New(
+		log,
+		commandGetter,
+		commandParamsGetter,
+		scriptRunner,
+		running,
+		results,
+	)))
+	executor.Set("cancel", withHistory(cancelExecution.New(log, running)))
+	executor.Set("mute", withHistory(mute.New(log)))
+	executor.Set("unmute", withHistory(unmute.New(log)))
+	executor.Set("set-volume", withHistory(setVolume.New(log)))
+	executor.Set("play-pause", withHistory(playPause.New(log)))
+	executor.Set("next-track", withHistory(nextTrack.New(log)))
+	executor.Set("prev-track", withHistory(prevTrack.New(log)))
+	executor.Set("notify", withHistory(showNotification.New(log)))
+	executor.Set("clipboard-set", withHistory(clipboardSet.New(log)))
+	executor.Set("clipboard-get", withHistory(clipboardGet.New(log, results)))
 
 	if err := executor.StartListen(localCtx, &commands.StartListenOptions{
 		CommandTopic:       fmt.Sprintf("pcs/%s/command", pcID),
@@ -0,0 +1,65 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	mqttMessage "smart-pc-agent/internal/domain/models/mqtt-message"
+
+	"github.com/MaxRomanov007/smart-pc-go-lib/domain/models/message"
+	mqttAuth "github.com/MaxRomanov007/smart-pc-go-lib/mqtt-auth"
+	"github.com/eclipse/paho.golang/paho"
+)
+
+type CommandResult struct {
+	Command string          `json:"command"`
+	Result  json.RawMessage `json:"result"`
+}
+
+type resultPublisher struct {
+	conn  *mqttAuth.Connection
+	topic string
+}
+
+func newResultPublisher(conn *mqttAuth.Connection, pcID string) *resultPublisher {
+	return &resultPublisher{
+		conn:  conn,
+		topic: fmt.Sprintf("pcs/%s/log", pcID),
+	}
+}
+
+func (p *resultPublisher) PublishResult(
+	ctx context.Context,
+	msg *message.Message,
+	result json.RawMessage,
+) error {
+	const op = "mqtt.resultPublisher.PublishResult"
+
+	payload, err := json.Marshal(mqttMessage.Message[CommandResult]{
+		Type: "pc-command-result",
+		Data: CommandResult{
+			Command: msg.Data.Command,
+			Result:  result,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("%s: failed to marshal result message: %w", op, err)
+	}
+
+	publish := &paho.Publish{
+		QoS:     1,
+		Topic:   p.topic,
+		Payload: payload,
+	}
+	if msg.Publish != nil && msg.Publish.Properties != nil {
+		publish.Properties = &paho.PublishProperties{
+			CorrelationData: msg.Publish.Properties.CorrelationData,
+		}
+	}
+
+	if _, err := p.conn.Publish(ctx, publish); err != nil {
+		return fmt.Errorf("%s: failed to publish result: %w", op, err)
+	}
+
+	return nil
+}
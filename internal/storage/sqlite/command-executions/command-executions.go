@@ -0,0 +1,167 @@
+package commandExecutions
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"smart-pc-agent/internal/domain/models"
+	"smart-pc-agent/internal/storage"
+	"smart-pc-agent/internal/storage/sqlite/dbqueries"
+	"time"
+)
+
+type Storage struct {
+	queries *dbqueries.Queries
+}
+
+func New(queries *dbqueries.Queries) *Storage {
+	return &Storage{queries}
+}
+
+func (s Storage) StartExecution(
+	ctx context.Context,
+	execution models.CommandExecution,
+) (models.CommandExecution, error) {
+	const op = "sqlite.command-executions.StartExecution"
+
+	created, err := s.queries.CreateCommandExecution(
+		ctx,
+		dbqueries.CreateCommandExecutionParams{
+			CommandID:  execution.CommandID,
+			MessageID:  execution.MessageID,
+			Parameters: string(execution.Parameters),
+			Status:     models.ExecutionStatusRunning,
+			StartedAt:  execution.StartedAt,
+		},
+	)
+	if err != nil {
+		return models.CommandExecution{}, fmt.Errorf(
+			"%s: failed to create command execution: %w",
+			op,
+			err,
+		)
+	}
+
+	return mapStorageExecution(created), nil
+}
+
+func (s Storage) FinishExecution(
+	ctx context.Context,
+	id int64,
+	status string,
+	errorMessage string,
+	finishedAt time.Time,
+) (models.CommandExecution, error) {
+	const op = "sqlite.command-executions.FinishExecution"
+
+	finished, err := s.queries.FinishCommandExecution(
+		ctx,
+		dbqueries.FinishCommandExecutionParams{
+			Status:       status,
+			ErrorMessage: errorMessage,
+			FinishedAt:   sql.NullTime{Time: finishedAt, Valid: true},
+			ID:           id,
+		},
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.CommandExecution{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return models.CommandExecution{}, fmt.Errorf(
+			"%s: failed to finish command execution: %w",
+			op,
+			err,
+		)
+	}
+
+	return mapStorageExecution(finished), nil
+}
+
+// PruneExecutions удаляет записи, начатые раньше startedBefore, и оставляет
+// у команды не больше keep последних; нулевые значения отключают ограничение
+func (s Storage) PruneExecutions(
+	ctx context.Context,
+	commandID string,
+	startedBefore time.Time,
+	keep int64,
+) error {
+	const op = "sqlite.command-executions.PruneExecutions"
+
+	if !startedBefore.IsZero() {
+		if err := s.queries.DeleteCommandExecutionsStartedBefore(ctx, startedBefore); err != nil {
+			return fmt.Errorf("%s: failed to delete old command executions: %w", op, err)
+		}
+	}
+
+	if keep > 0 {
+		if err := s.queries.DeleteCommandExecutionsExceptLatest(
+			ctx,
+			dbqueries.DeleteCommandExecutionsExceptLatestParams{
+				CommandID: commandID,
+				Keep:      keep,
+			},
+		); err != nil {
+			return fmt.Errorf("%s: failed to delete extra command executions: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+func (s Storage) GetCommandExecutions(
+	ctx context.Context,
+	commandID string,
+	limit int64,
+	offset int64,
+) ([]models.CommandExecution, int64, error) {
+	const op = "sqlite.command-executions.GetCommandExecutions"
+
+	executions, err := s.queries.GetCommandExecutions(
+		ctx,
+		dbqueries.GetCommandExecutionsParams{
+			CommandID:  commandID,
+			PageLimit:  limit,
+			PageOffset: offset,
+		},
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to get command executions: %w", op, err)
+	}
+
+	total, err := s.queries.CountCommandExecutions(ctx, commandID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: failed to count command executions: %w", op, err)
+	}
+
+	return mapStorageExecutions(executions), total, nil
+}
+
+func mapStorageExecutions(raw []dbqueries.CommandExecution) []models.CommandExecution {
+	executions := make([]models.CommandExecution, len(raw))
+	for i, execution := range raw {
+		executions[i] = mapStorageExecution(execution)
+	}
+	return executions
+}
+
+func mapStorageExecution(execution dbqueries.CommandExecution) models.CommandExecution {
+	mapped := models.CommandExecution{
+		ID:           execution.ID,
+		CommandID:    execution.CommandID,
+		MessageID:    execution.MessageID,
+		Status:       execution.Status,
+		ErrorMessage: execution.ErrorMessage,
+		StartedAt:    execution.StartedAt,
+	}
+
+	if execution.Parameters != "" && json.Valid([]byte(execution.Parameters)) {
+		mapped.Parameters = json.RawMessage(execution.Parameters)
+	}
+	if execution.FinishedAt.Valid {
+		mapped.FinishedAt = &execution.FinishedAt.Time
+	}
+
+	return mapped
+}
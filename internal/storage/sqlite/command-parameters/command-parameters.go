@@ -2,6 +2,8 @@ package commandParameters
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"smart-pc-agent/internal/domain/models"
 	"smart-pc-agent/internal/storage/sqlite/dbqueries"
@@ -26,21 +28,86 @@ func (s Storage) GetCommandParams(
 		return nil, fmt.Errorf("%s: failed to get command params: %w", op, err)
 	}
 
-	return mapStorageParams(params), nil
+	result, err := mapStorageParams(params)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, nil
 }
 
-func mapStorageParams(raw []dbqueries.CommandParam) []models.CommandParameter {
+func mapStorageParams(raw []dbqueries.CommandParam) ([]models.CommandParameter, error) {
 	params := make([]models.CommandParameter, len(raw))
 	for i, param := range raw {
-		params[i] = mapStorageParam(param)
+		mapped, err := MapStorageParam(param)
+		if err != nil {
+			return nil, err
+		}
+		params[i] = mapped
 	}
-	return params
+	return params, nil
 }
 
-func mapStorageParam(param dbqueries.CommandParam) models.CommandParameter {
-	return models.CommandParameter{
+// MapStorageParam переводит строку command_params в модель
+func MapStorageParam(param dbqueries.CommandParam) (models.CommandParameter, error) {
+	result := models.CommandParameter{
 		CommandID: param.CommandID,
 		Name:      param.Name,
 		Type:      param.Type,
+		Required:  param.Required,
+	}
+	if param.ItemType.Valid {
+		result.ItemType = int16(param.ItemType.Int64)
+	}
+	if param.DefaultValue.Valid {
+		result.Default = &param.DefaultValue.String
+	}
+	if param.MinValue.Valid {
+		result.Min = &param.MinValue.Float64
+	}
+	if param.MaxValue.Valid {
+		result.Max = &param.MaxValue.Float64
+	}
+	// допустимые значения хранятся JSON-массивом, пустая строка — без ограничения
+	if param.EnumValues != "" {
+		if err := json.Unmarshal([]byte(param.EnumValues), &result.Enum); err != nil {
+			return models.CommandParameter{}, fmt.Errorf(
+				"failed to decode enum of parameter %s: %w",
+				param.Name,
+				err,
+			)
+		}
+	}
+	return result, nil
+}
+
+// UpsertParams готовит параметры запроса CreateOrUpdateCommandParameter
+func UpsertParams(
+	commandID string,
+	param models.CommandParameter,
+) dbqueries.CreateOrUpdateCommandParameterParams {
+	result := dbqueries.CreateOrUpdateCommandParameterParams{
+		CommandID: commandID,
+		Name:      param.Name,
+		Type:      param.Type,
+		Required:  param.Required,
+	}
+	if param.ItemType != 0 {
+		result.ItemType = sql.NullInt64{Int64: int64(param.ItemType), Valid: true}
+	}
+	if param.Default != nil {
+		result.DefaultValue = sql.NullString{String: *param.Default, Valid: true}
+	}
+	if param.Min != nil {
+		result.MinValue = sql.NullFloat64{Float64: *param.Min, Valid: true}
+	}
+	if param.Max != nil {
+		result.MaxValue = sql.NullFloat64{Float64: *param.Max, Valid: true}
+	}
+	if len(param.Enum) > 0 {
+		// срез строк всегда сериализуется
+		enum, _ := json.Marshal(param.Enum)
+		result.EnumValues = string(enum)
 	}
+	return result
 }
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"smart-pc-agent/internal/domain/models"
 	"smart-pc-agent/internal/storage"
+	commandParameters "smart-pc-agent/internal/storage/sqlite/command-parameters"
 	"smart-pc-agent/internal/storage/sqlite/dbqueries"
+	"strings"
 )
 
 type Storage struct {
@@ -81,8 +83,10 @@ func (s Storage) CreateCommand(
 	queries := dbqueries.New(tx)
 
 	createdCommand, err := queries.CreateCommand(ctx, dbqueries.CreateCommandParams{
-		ID:     command.ID,
-		Script: command.Script,
+		ID:           command.ID,
+		Script:       command.Script,
+		TimeoutMs:    command.TimeoutMs,
+		Capabilities: joinCapabilities(command.Capabilities),
 	})
 	if err != nil {
 		return models.Command{}, fmt.Errorf("%s: failed to create command: %w", op, err)
@@ -95,11 +99,7 @@ func (s Storage) CreateCommand(
 	for _, param := range command.Parameters {
 		_, err := queries.CreateOrUpdateCommandParameter(
 			ctx,
-			dbqueries.CreateOrUpdateCommandParameterParams{
-				CommandID: command.ID,
-				Name:      param.Name,
-				Type:      param.Type,
-			},
+			commandParameters.UpsertParams(command.ID, param),
 		)
 		if err != nil {
 			return models.Command{}, fmt.Errorf(
@@ -114,10 +114,39 @@ func (s Storage) CreateCommand(
 	return mapStorageCommand(createdCommand), nil
 }
 
-func (s Storage) DeleteCommand(ctx context.Context, id string) (models.Command, error) {
-	const op = "sqlite.commands.GetCommandScript"
+// DeleteCommand удаляет команду вместе с параметрами, хранилищем скрипта
+// и историей выполнения
+func (s Storage) DeleteCommand(ctx context.Context, id string) (deleted models.Command, err error) {
+	const op = "sqlite.commands.DeleteCommand"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Command{}, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf(
+					"%s: failed to rollback (error: %w), after operation failed (error: %w)",
+					op,
+					rollbackErr,
+					err,
+				)
+			}
+			return
+		}
+
+		commitErr := tx.Commit()
+		if commitErr != nil {
+			err = fmt.Errorf("%s: failed to commit transaction: %w", op, commitErr)
+		}
+	}()
+
+	queries := dbqueries.New(tx)
 
-	command, err := s.queries.DeleteCommand(ctx, id)
+	command, err := queries.DeleteCommand(ctx, id)
 	if errors.Is(err, sql.ErrNoRows) {
 		return models.Command{}, storage.ErrNotFound
 	}
@@ -125,10 +154,18 @@ func (s Storage) DeleteCommand(ctx context.Context, id string) (models.Command,
 		return models.Command{}, fmt.Errorf("%s: failed to delete command by id: %w", op, err)
 	}
 
-	if err := s.queries.DeleteCommandParameters(ctx, id); err != nil {
+	if err := queries.DeleteCommandParameters(ctx, id); err != nil {
 		return models.Command{}, fmt.Errorf("%s: failed to delete command parameters: %w", op, err)
 	}
 
+	if err := queries.DeleteScriptStorage(ctx, id); err != nil {
+		return models.Command{}, fmt.Errorf("%s: failed to delete script storage: %w", op, err)
+	}
+
+	if err := queries.DeleteCommandExecutions(ctx, id); err != nil {
+		return models.Command{}, fmt.Errorf("%s: failed to delete command executions: %w", op, err)
+	}
+
 	return mapStorageCommand(command), nil
 }
 
@@ -165,18 +202,22 @@ func (s Storage) UpdateCommand(
 
 	queries := dbqueries.New(tx)
 
-	updatedCommand, err := queries.UpdateCommandScript(ctx, dbqueries.UpdateCommandScriptParams{
-		Script: command.Script,
-		ID:     command.ID,
+	updatedCommand, err := queries.UpdateCommand(ctx, dbqueries.UpdateCommandParams{
+		Script:       command.Script,
+		TimeoutMs:    command.TimeoutMs,
+		Capabilities: joinCapabilities(command.Capabilities),
+		ID:           command.ID,
 	})
 	if errors.Is(err, sql.ErrNoRows) {
 		return models.Command{}, storage.ErrNotFound
 	}
 	if err != nil {
-		return models.Command{}, fmt.Errorf("%s: failed to update command script: %w", op, err)
+		return models.Command{}, fmt.Errorf("%s: failed to update command: %w", op, err)
 	}
 
 	command.Script = updatedCommand.Script
+	command.TimeoutMs = updatedCommand.TimeoutMs
+	command.Capabilities = splitCapabilities(updatedCommand.Capabilities)
 
 	if command.Parameters == nil {
 		return command, nil
@@ -186,11 +227,7 @@ func (s Storage) UpdateCommand(
 	for i, param := range command.Parameters {
 		storageParam, err := queries.CreateOrUpdateCommandParameter(
 			ctx,
-			dbqueries.CreateOrUpdateCommandParameterParams{
-				CommandID: command.ID,
-				Name:      param.Name,
-				Type:      param.Type,
-			},
+			commandParameters.UpsertParams(command.ID, param),
 		)
 		if err != nil {
 			return models.Command{}, fmt.Errorf(
@@ -202,7 +239,10 @@ func (s Storage) UpdateCommand(
 		}
 
 		newNames[i] = storageParam.Name
-		command.Parameters[i] = mapStorageCommandParam(storageParam)
+		command.Parameters[i], err = commandParameters.MapStorageParam(storageParam)
+		if err != nil {
+			return models.Command{}, fmt.Errorf("%s: %w", op, err)
+		}
 	}
 
 	if err := queries.DeleteCommandParametersExceptNames(
@@ -220,15 +260,21 @@ func (s Storage) UpdateCommand(
 
 func mapStorageCommand(command dbqueries.Command) models.Command {
 	return models.Command{
-		ID:     command.ID,
-		Script: command.Script,
+		ID:           command.ID,
+		Script:       command.Script,
+		TimeoutMs:    command.TimeoutMs,
+		Capabilities: splitCapabilities(command.Capabilities),
 	}
 }
 
-func mapStorageCommandParam(param dbqueries.CommandParam) models.CommandParameter {
-	return models.CommandParameter{
-		CommandID: param.CommandID,
-		Name:      param.Name,
-		Type:      param.Type,
+// возможности хранятся в одной колонке через запятую
+func joinCapabilities(capabilities []string) string {
+	return strings.Join(capabilities, ",")
+}
+
+func splitCapabilities(capabilities string) []string {
+	if capabilities == "" {
+		return nil
 	}
+	return strings.Split(capabilities, ",")
 }
@@ -0,0 +1,119 @@
+package libraries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"smart-pc-agent/internal/domain/models"
+	"smart-pc-agent/internal/storage"
+	"smart-pc-agent/internal/storage/sqlite/dbqueries"
+	"time"
+)
+
+type Storage struct {
+	queries *dbqueries.Queries
+}
+
+func New(queries *dbqueries.Queries) *Storage {
+	return &Storage{queries}
+}
+
+func (s Storage) GetLibraries(ctx context.Context) ([]models.Library, error) {
+	const op = "sqlite.libraries.GetLibraries"
+
+	libraries, err := s.queries.GetLibraries(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get libraries: %w", op, err)
+	}
+
+	result := make([]models.Library, len(libraries))
+	for i, library := range libraries {
+		result[i] = mapStorageLibrary(library)
+	}
+
+	return result, nil
+}
+
+func (s Storage) GetLibrary(ctx context.Context, name string) (models.Library, error) {
+	const op = "sqlite.libraries.GetLibrary"
+
+	library, err := s.queries.GetLibrary(ctx, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Library{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return models.Library{}, fmt.Errorf("%s: failed to get library: %w", op, err)
+	}
+
+	return mapStorageLibrary(library), nil
+}
+
+func (s Storage) CreateLibrary(
+	ctx context.Context,
+	library models.Library,
+) (models.Library, error) {
+	const op = "sqlite.libraries.CreateLibrary"
+
+	created, err := s.queries.CreateLibrary(ctx, dbqueries.CreateLibraryParams{
+		Name:        library.Name,
+		Description: library.Description,
+		Source:      library.Source,
+		UpdatedAt:   time.Now().UTC(),
+	})
+	// при конфликте имени вставка не выполняется и строк не возвращается
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Library{}, storage.ErrAlreadyExists
+	}
+	if err != nil {
+		return models.Library{}, fmt.Errorf("%s: failed to create library: %w", op, err)
+	}
+
+	return mapStorageLibrary(created), nil
+}
+
+func (s Storage) UpdateLibrary(
+	ctx context.Context,
+	library models.Library,
+) (models.Library, error) {
+	const op = "sqlite.libraries.UpdateLibrary"
+
+	updated, err := s.queries.UpdateLibrary(ctx, dbqueries.UpdateLibraryParams{
+		Description: library.Description,
+		Source:      library.Source,
+		UpdatedAt:   time.Now().UTC(),
+		Name:        library.Name,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Library{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return models.Library{}, fmt.Errorf("%s: failed to update library: %w", op, err)
+	}
+
+	return mapStorageLibrary(updated), nil
+}
+
+func (s Storage) DeleteLibrary(ctx context.Context, name string) (models.Library, error) {
+	const op = "sqlite.libraries.DeleteLibrary"
+
+	deleted, err := s.queries.DeleteLibrary(ctx, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Library{}, storage.ErrNotFound
+	}
+	if err != nil {
+		return models.Library{}, fmt.Errorf("%s: failed to delete library: %w", op, err)
+	}
+
+	return mapStorageLibrary(deleted), nil
+}
+
+func mapStorageLibrary(library dbqueries.Library) models.Library {
+	return models.Library{
+		Name:        library.Name,
+		Description: library.Description,
+		Source:      library.Source,
+		Version:     library.Version,
+		UpdatedAt:   library.UpdatedAt,
+	}
+}
@@ -7,6 +7,24 @@ import (
 	"smart-pc-agent/data/schema"
 )
 
+// migration дополняет таблицы, созданные старыми версиями агента, до
+// актуальной схемы. schema.sql создаёт только недостающие таблицы, поэтому
+// миграция должна ничего не менять в базе, которая уже создана по schema.sql.
+type migration func(ctx context.Context, tx *sql.Tx) error
+
+// migrations применяются по порядку, количество применённых хранится
+// в PRAGMA user_version. Новые миграции добавляются только в конец.
+var migrations = []migration{
+	addColumn("commands", "timeout_ms", "INTEGER NOT NULL DEFAULT 0 CHECK (timeout_ms >= 0)"),
+	addColumn("commands", "capabilities", "TEXT NOT NULL DEFAULT ''"),
+	addColumn("command_params", "required", "BOOLEAN NOT NULL DEFAULT FALSE"),
+	addColumn("command_params", "default_value", "TEXT"),
+	addColumn("command_params", "min_value", "REAL"),
+	addColumn("command_params", "max_value", "REAL"),
+	addColumn("command_params", "enum_values", "TEXT NOT NULL DEFAULT ''"),
+	rebuildCommandParams,
+}
+
 func migrate(db *sql.DB, ctx context.Context) (err error) {
 	const op = "storage.sqlite.migrate"
 
@@ -14,5 +32,139 @@ func migrate(db *sql.DB, ctx context.Context) (err error) {
 		return fmt.Errorf("%s: failed to execute script: %w", op, err)
 	}
 
+	var version int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("%s: failed to get schema version: %w", op, err)
+	}
+	if version > len(migrations) {
+		return fmt.Errorf(
+			"%s: database schema version %d is newer than supported %d",
+			op,
+			version,
+			len(migrations),
+		)
+	}
+
+	for ; version < len(migrations); version++ {
+		if err := applyMigration(ctx, db, version); err != nil {
+			return fmt.Errorf("%s: failed to apply migration %d: %w", op, version+1, err)
+		}
+	}
+
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, index int) (err error) {
+	const op = "storage.sqlite.applyMigration"
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+
+	defer func() {
+		if err != nil {
+			rollbackErr := tx.Rollback()
+			if rollbackErr != nil {
+				err = fmt.Errorf(
+					"%s: failed to rollback (error: %w), after operation failed (error: %w)",
+					op,
+					rollbackErr,
+					err,
+				)
+			}
+			return
+		}
+
+		commitErr := tx.Commit()
+		if commitErr != nil {
+			err = fmt.Errorf("%s: failed to commit transaction: %w", op, commitErr)
+		}
+	}()
+
+	if err := migrations[index](ctx, tx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// PRAGMA не принимает параметры запроса
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", index+1)); err != nil {
+		return fmt.Errorf("%s: failed to set schema version: %w", op, err)
+	}
+
+	return nil
+}
+
+// addColumn добавляет столбец, если таблица создана без него
+func addColumn(table, column, definition string) migration {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		exists, err := columnExists(ctx, tx, table, column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+
+		query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+		}
+		return nil
+	}
+}
+
+func columnExists(ctx context.Context, tx *sql.Tx, table, column string) (bool, error) {
+	var count int
+	err := tx.QueryRowContext(
+		ctx,
+		"SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?",
+		table,
+		column,
+	).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check column %s.%s: %w", table, column, err)
+	}
+	return count > 0, nil
+}
+
+// rebuildCommandParams пересоздаёт command_params по актуальной схеме:
+// SQLite не умеет менять CHECK столбца, а старые таблицы допускают только
+// типы 1..3 и не содержат item_type
+func rebuildCommandParams(ctx context.Context, tx *sql.Tx) error {
+	exists, err := columnExists(ctx, tx, "command_params", "item_type")
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	statements := []string{
+		`CREATE TABLE command_params_new
+(
+    command_id    TEXT         NOT NULL REFERENCES commands (id),
+    name          VARCHAR(255) NOT NULL,
+    type          SMALLINT     NOT NULL CHECK (type >= 1 AND type <= 8),
+    item_type     SMALLINT CHECK (item_type IN (2, 3, 4)),
+    required      BOOLEAN      NOT NULL DEFAULT FALSE,
+    default_value TEXT,
+    min_value     REAL,
+    max_value     REAL,
+    enum_values   TEXT         NOT NULL DEFAULT '',
+
+    PRIMARY KEY (command_id, name)
+)`,
+		`INSERT INTO command_params_new
+    (command_id, name, type, required, default_value, min_value, max_value, enum_values)
+SELECT command_id, name, type, required, default_value, min_value, max_value, enum_values
+FROM command_params`,
+		`DROP TABLE command_params`,
+		`ALTER TABLE command_params_new RENAME TO command_params`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to rebuild table command_params: %w", err)
+		}
+	}
 	return nil
 }
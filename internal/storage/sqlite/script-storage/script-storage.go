@@ -0,0 +1,78 @@
+package scriptStorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"smart-pc-agent/internal/storage"
+	"smart-pc-agent/internal/storage/sqlite/dbqueries"
+)
+
+type Storage struct {
+	queries *dbqueries.Queries
+}
+
+func New(queries *dbqueries.Queries) *Storage {
+	return &Storage{queries}
+}
+
+func (s Storage) GetScriptValue(ctx context.Context, commandID string, key string) (string, error) {
+	const op = "sqlite.script-storage.GetScriptValue"
+
+	data, err := s.queries.GetScriptStorageValue(ctx, dbqueries.GetScriptStorageValueParams{
+		CommandID: commandID,
+		Key:       key,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", storage.ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to get value: %w", op, err)
+	}
+
+	return data.Value, nil
+}
+
+func (s Storage) SetScriptValue(
+	ctx context.Context,
+	commandID string,
+	key string,
+	value string,
+) error {
+	const op = "sqlite.script-storage.SetScriptValue"
+
+	if err := s.queries.SetScriptStorageValue(ctx, dbqueries.SetScriptStorageValueParams{
+		CommandID: commandID,
+		Key:       key,
+		Value:     value,
+	}); err != nil {
+		return fmt.Errorf("%s: failed to set value: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s Storage) DeleteScriptValue(ctx context.Context, commandID string, key string) error {
+	const op = "sqlite.script-storage.DeleteScriptValue"
+
+	if err := s.queries.DeleteScriptStorageValue(ctx, dbqueries.DeleteScriptStorageValueParams{
+		CommandID: commandID,
+		Key:       key,
+	}); err != nil {
+		return fmt.Errorf("%s: failed to delete value: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s Storage) GetScriptKeys(ctx context.Context, commandID string) ([]string, error) {
+	const op = "sqlite.script-storage.GetScriptKeys"
+
+	keys, err := s.queries.GetScriptStorageKeys(ctx, commandID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get keys: %w", op, err)
+	}
+
+	return keys, nil
+}
@@ -10,9 +10,12 @@ import (
 	"path/filepath"
 	"smart-pc-agent/internal/config"
 	appStorage "smart-pc-agent/internal/storage/sqlite/app-storage"
+	commandExecutions "smart-pc-agent/internal/storage/sqlite/command-executions"
 	commandParameters "smart-pc-agent/internal/storage/sqlite/command-parameters"
 	"smart-pc-agent/internal/storage/sqlite/commands"
 	"smart-pc-agent/internal/storage/sqlite/dbqueries"
+	"smart-pc-agent/internal/storage/sqlite/libraries"
+	scriptStorage "smart-pc-agent/internal/storage/sqlite/script-storage"
 
 	"github.com/MaxRomanov007/smart-pc-go-lib/logger/sl"
 	_ "github.com/mattn/go-sqlite3"
@@ -22,6 +25,9 @@ type Storage struct {
 	AppStorage        *appStorage.Storage
 	Commands          *commands.Storage
 	CommandParameters *commandParameters.Storage
+	CommandExecutions *commandExecutions.Storage
+	ScriptStorage     *scriptStorage.Storage
+	Libraries         *libraries.Storage
 	queries           *dbqueries.Queries
 }
 
@@ -53,6 +59,9 @@ func New(ctx context.Context, log *slog.Logger, cfg config.Storage) (*Storage, e
 		AppStorage:        appStorage.New(queries),
 		Commands:          commands.New(db),
 		CommandParameters: commandParameters.New(queries),
+		CommandExecutions: commandExecutions.New(queries),
+		ScriptStorage:     scriptStorage.New(queries),
+		Libraries:         libraries.New(queries),
 		queries:           queries,
 	}, nil
 }
@@ -98,5 +107,17 @@ func (s *Storage) CleanDb(ctx context.Context) error {
 		return fmt.Errorf("%s: failed to delete all parameters: %w", op, err)
 	}
 
+	if err := s.queries.DeleteAllCommandExecutions(ctx); err != nil {
+		return fmt.Errorf("%s: failed to delete all command executions: %w", op, err)
+	}
+
+	if err := s.queries.DeleteAllScriptStorage(ctx); err != nil {
+		return fmt.Errorf("%s: failed to delete all script storage: %w", op, err)
+	}
+
+	if err := s.queries.DeleteAllLibraries(ctx); err != nil {
+		return fmt.Errorf("%s: failed to delete all libraries: %w", op, err)
+	}
+
 	return nil
 }